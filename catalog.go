@@ -0,0 +1,372 @@
+package vx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The messages of a locale by the code of the error, like "minLength" or
+// "type". Used for the validations with the locale, see `WithLocale`.
+//
+// In JSON, a message is either a template or its plural forms along with the
+// param that picks the form, see `Message`.
+//
+// Ex:
+//
+//	{
+//		"required": "{label} ist erforderlich",
+//		"minLength": {
+//			"count": "limit",
+//			"one": "{label} braucht mindestens {limit} Zeichen",
+//			"other": "{label} braucht mindestens {limit} Zeichen"
+//		}
+//	}
+type Catalog map[string]Message
+
+// A message template of a `Catalog`, with the same placeholders as the ones
+// of `RegisterMessage`.
+//
+// A message with plural forms picks one by the number in the param `Count`
+// of the error, with the `PluralRule` of the language. The "other" form is
+// used when there is no form for the number, and is the only form of a
+// message without plurals.
+type Message struct {
+	// The param with the number that picks the form, like "limit".
+	Count string
+	// The templates by CLDR plural category: "zero", "one", "two", "few",
+	// "many" and "other".
+	Forms map[string]string
+}
+
+// Gives the plural category of `n` in a language, one of "zero", "one",
+// "two", "few", "many" and "other".
+type PluralRule func(n int) string
+
+var pluralCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var template string
+	if err := json.Unmarshal(data, &template); err == nil {
+		*m = Message{Forms: map[string]string{"other": template}}
+		return nil
+	}
+
+	var forms map[string]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return fmt.Errorf("message should be a template or an object of plural forms: %w", err)
+	}
+
+	msg := Message{Count: forms["count"], Forms: map[string]string{}}
+	delete(forms, "count")
+
+	for category, template := range forms {
+		if !contains(pluralCategories, category) {
+			return fmt.Errorf("message has an unknown plural category %q, expected one of %s", category, strings.Join(pluralCategories, ", "))
+		}
+
+		msg.Forms[category] = template
+	}
+
+	if _, ok := msg.Forms["other"]; !ok {
+		return fmt.Errorf(`message should have the "other" form`)
+	}
+
+	*m = msg
+	return nil
+}
+
+func (m Message) MarshalJSON() ([]byte, error) {
+	if m.Count == "" && len(m.Forms) == 1 {
+		return json.Marshal(m.Forms["other"])
+	}
+
+	forms := map[string]string{}
+	for category, template := range m.Forms {
+		forms[category] = template
+	}
+
+	if m.Count != "" {
+		forms["count"] = m.Count
+	}
+
+	return json.Marshal(forms)
+}
+
+// Gives the template for an error with the `params`.
+func (m Message) template(params map[string]any, rule PluralRule) string {
+	if n, ok := count(params[m.Count]); ok && m.Count != "" {
+		if template, ok := m.Forms[rule(n)]; ok {
+			return template
+		}
+	}
+
+	return m.Forms["other"]
+}
+
+func count(v any) (int, bool) {
+	if n, ok := v.(json.Number); ok {
+		i, err := strconv.Atoi(n.String())
+		return i, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return int(rv.Float()), true
+	}
+
+	return 0, false
+}
+
+var catalogs = struct {
+	sync.RWMutex
+	byLocale map[string]Catalog
+	plurals  map[string]PluralRule
+}{
+	byLocale: map[string]Catalog{},
+	plurals: map[string]PluralRule{
+		"fr": func(n int) string {
+			if n == 0 || n == 1 {
+				return "one"
+			}
+
+			return "other"
+		},
+		"ru": slavicPlural,
+		"uk": slavicPlural,
+		"pl": func(n int) string {
+			if n == 1 {
+				return "one"
+			}
+
+			if slavicPlural(n) == "few" {
+				return "few"
+			}
+
+			return "many"
+		},
+		"ja": noPlural,
+		"ko": noPlural,
+		"zh": noPlural,
+	},
+}
+
+func defaultPlural(n int) string {
+	if n == 1 {
+		return "one"
+	}
+
+	return "other"
+}
+
+func noPlural(n int) string {
+	return "other"
+}
+
+func slavicPlural(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// Adds the messages to the catalog of the `locale`, like "de" or "pt-BR",
+// replacing the ones with the same codes.
+func RegisterCatalog(locale string, catalog Catalog) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	locale = normalizeLocale(locale)
+
+	existing, ok := catalogs.byLocale[locale]
+	if !ok {
+		existing = Catalog{}
+		catalogs.byLocale[locale] = existing
+	}
+
+	for code, msg := range catalog {
+		existing[code] = msg
+	}
+}
+
+// Sets the `PluralRule` of a language, like "ar". The rule of English, "one"
+// for 1 and "other" for the rest, is used for the languages without one.
+func RegisterPluralRule(language string, rule PluralRule) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	catalogs.plurals[normalizeLocale(language)] = rule
+}
+
+// Removes all the catalogs, like for a test to start from none or to reload
+// them. The plural rules stay.
+func ResetCatalogs() {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	catalogs.byLocale = map[string]Catalog{}
+}
+
+// Registers the catalogs in the JSON files of `dir`, the name of a file being
+// its locale like "de.json" or "pt-BR.json". Works with `embed.FS` as well as
+// `os.DirFS`.
+//
+// Either all of the files are registered or, when one of them can't be read
+// or parsed, none of them are.
+//
+// Ex:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	err := vx.LoadCatalogs(locales, "locales")
+func LoadCatalogs(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	loaded := map[string]Catalog{}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		loaded[strings.TrimSuffix(path.Base(file), ".json")] = catalog
+	}
+
+	for locale, catalog := range loaded {
+		RegisterCatalog(locale, catalog)
+	}
+
+	return nil
+}
+
+// Gives the template of the catalog for the error with the `code`, from the
+// first of the `locales` that has it.
+func catalogMessage(locales []string, code string, params map[string]any) (string, bool) {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	for _, locale := range locales {
+		for _, candidate := range localeCandidates(locale) {
+			msg, ok := catalogs.byLocale[candidate][code]
+			if !ok {
+				continue
+			}
+
+			rule, ok := catalogs.plurals[language(candidate)]
+			if !ok {
+				rule = defaultPlural
+			}
+
+			return msg.template(params, rule), true
+		}
+	}
+
+	return "", false
+}
+
+// Gives the locales to look for, from the most specific one, like "pt-br"
+// and then "pt" for "pt-BR".
+func localeCandidates(locale string) []string {
+	candidates := []string{}
+
+	for locale != "" {
+		candidates = append(candidates, locale)
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+
+		locale = locale[:i]
+	}
+
+	return candidates
+}
+
+func language(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	return lang
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// Gives the locales of an Accept-Language header by their preference, like
+// ["de-ch", "de", "en"] for "de-CH, de;q=0.9, en;q=0.8, *;q=0.5".
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	ranges := []weighted{}
+
+	for _, item := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(item, ";")
+		locale = normalizeLocale(locale)
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		if locale == "" || locale == "*" || q <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, weighted{locale, q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	locales := []string{}
+	for _, r := range ranges {
+		locales = append(locales, r.locale)
+	}
+
+	return locales
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
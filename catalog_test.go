@@ -0,0 +1,146 @@
+package vx
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCatalog(t *testing.T) {
+	type signup struct {
+		Name any `json:"name" vx:"name=name, type=string, required, minLength=3"`
+		Nick any `json:"nick" vx:"name=nick, type=string, minLength=1"`
+	}
+
+	fsys := fstest.MapFS{
+		"locales/de.json": {Data: []byte(`{
+			"required": "{label} ist erforderlich",
+			"minLength": {"count": "limit", "one": "{label} braucht mindestens ein Zeichen", "other": "{label} braucht mindestens {limit} Zeichen"}
+		}`)},
+		"locales/ru.json": {Data: []byte(`{
+			"minLength": {"count": "limit", "one": "{label}: минимум {limit} символ", "few": "{label}: минимум {limit} символа", "many": "{label}: минимум {limit} символов"}
+		}`)},
+		"locales/pt-BR.json": {Data: []byte(`{"required": "{label} é obrigatório"}`)},
+	}
+
+	t.Cleanup(ResetCatalogs)
+
+	if err := LoadCatalogs(fsys, "locales"); err == nil {
+		t.Fatalf("expected an error for the catalog without the other form")
+	}
+
+	if res, _ := ValidateStruct(signup{Nick: "a"}, WithLocale("de")); len(res.Errors) != 1 || res.Errors[0].Error() != "name is required" {
+		t.Fatalf("expected no catalog to be registered after the error but got %v", res.Errors)
+	}
+
+	fsys["locales/ru.json"].Data = []byte(`{
+		"minLength": {"count": "limit", "one": "{label}: минимум {limit} символ", "few": "{label}: минимум {limit} символа", "other": "{label}: минимум {limit} символов"}
+	}`)
+
+	if err := LoadCatalogs(fsys, "locales"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		value    signup
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:     "no locale should keep the default messages",
+			value:    signup{Nick: ""},
+			expected: []string{"name is required", "nick should have a minimum length of 1 but has 0"},
+		},
+		{
+			name:     "plural forms should be picked by the count",
+			value:    signup{Nick: ""},
+			opts:     []Option{WithLocale("de")},
+			expected: []string{"name ist erforderlich", "nick braucht mindestens ein Zeichen"},
+		},
+		{
+			name:     "other form should be used for the rest",
+			value:    signup{Name: "ab", Nick: "a"},
+			opts:     []Option{WithLocale("de-AT")},
+			expected: []string{"name braucht mindestens 3 Zeichen"},
+		},
+		{
+			name:     "plural rule of the language should be used",
+			value:    signup{Name: "ab", Nick: ""},
+			opts:     []Option{WithLocale("ru")},
+			expected: []string{"name: минимум 3 символа", "nick: минимум 1 символ"},
+		},
+		{
+			name:     "messages should fall back to the next locale",
+			value:    signup{Nick: ""},
+			opts:     []Option{WithAcceptLanguage("pt-BR, de;q=0.5, en;q=0.8")},
+			expected: []string{"name é obrigatório", "nick braucht mindestens ein Zeichen"},
+		},
+		{
+			name:     "unknown locales should keep the default messages",
+			value:    signup{Nick: ""},
+			opts:     []Option{WithAcceptLanguage("fr-CH, *;q=0.5")},
+			expected: []string{"name is required", "nick should have a minimum length of 1 but has 0"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, _ := ValidateStruct(tc.value, tc.opts...)
+
+			if len(res.Errors) != len(tc.expected) {
+				t.Fatalf("expected %d errors but got %v", len(tc.expected), res.Errors)
+			}
+
+			for i, err := range res.Errors {
+				if err.Error() != tc.expected[i] {
+					t.Errorf("expected %q but got %q", tc.expected[i], err.Error())
+				}
+			}
+		})
+	}
+
+	t.Run("messages should round trip as JSON", func(t *testing.T) {
+		catalog := Catalog{
+			"required":  {Forms: map[string]string{"other": "{label} is required"}},
+			"minLength": {Count: "limit", Forms: map[string]string{"one": "a", "other": "b"}},
+		}
+
+		data, err := json.Marshal(catalog)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := `{"minLength":{"count":"limit","one":"a","other":"b"},"required":"{label} is required"}`
+		if string(data) != expected {
+			t.Errorf("expected %s but got %s", expected, data)
+		}
+	})
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected []string
+	}{
+		{"", []string{}},
+		{"de-CH, de;q=0.9, en;q=0.8, *;q=0.5", []string{"de-ch", "de", "en"}},
+		{"en;q=0.5, fr, pt_BR;q=0.7, ja;q=0", []string{"fr", "pt-br", "en"}},
+	}
+
+	for _, tc := range tests {
+		got := parseAcceptLanguage(tc.header)
+
+		if len(got) != len(tc.expected) {
+			t.Errorf("%q: expected %v but got %v", tc.header, tc.expected, got)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != tc.expected[i] {
+				t.Errorf("%q: expected %v but got %v", tc.header, tc.expected, got)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package vx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"vx/internal"
+)
+
+// Decodes the JSON object in `r` into the struct `v` points to and validates
+// it with `ValidateStruct`. Unlike encoding/json, which silently drops the
+// keys that aren't fields of the struct, every such key is reported as an
+// error with the code "unknownField", nested ones included. The error hints
+// at the field it was probably meant to be, like
+// "filtr is not a known field, did you mean filter?".
+//
+// Ex:
+// res, ok := vx.Decode(req.Body, &u, vx.AllowUnknownFields("meta"))
+//
+// Keys can be let through with `AllowUnknownFields`. A `r` which isn't a JSON
+// object that fits in the struct is reported as an error with the code
+// "invalidJSON", with `ok` as true as it's the input that is invalid.
+func Decode(r io.Reader, v any, opts ...Option) (res VxResult, ok bool) {
+	res = VxResult{
+		Errors: []error{},
+	}
+
+	if val := reflect.ValueOf(v); val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		res.Errors = append(res.Errors, fmt.Errorf("decode: expected a pointer to struct, received %s", val.Kind()))
+		return res, false
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		res.Errors = append(res.Errors, fmt.Errorf("decode: couldn't read the input. %s", err.Error()))
+		return res, false
+	}
+
+	o := makeOptions(opts)
+
+	var doc any
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(&doc); err != nil {
+		res.Errors = append(res.Errors, invalidJSON(err, o))
+		return res, true
+	}
+
+	if m, isObj := doc.(map[string]any); isObj {
+		schema := schemaFor(reflect.TypeOf(v).Elem())
+		allowed := func(name string) bool {
+			return matchesAny(name, o.allowedUnknown)
+		}
+
+		for _, key := range schema.doc.UnknownKeys(m, allowed) {
+			fieldErr := &FieldError{Field: key.Name, Path: key.Path, Code: "unknownField", Err: key}
+			if key.Suggestion != "" {
+				fieldErr.Params = map[string]any{"suggestion": key.Suggestion}
+			}
+
+			applyMessage(fieldErr, nil, nil, sameLabel, o)
+			res.Errors = append(res.Errors, fieldErr)
+		}
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		res.Errors = append(res.Errors, invalidJSON(err, o))
+		return res, true
+	}
+
+	// The unknown keys count towards `MaxErrors` as well.
+	if o.maxErrors > 0 && len(res.Errors) > o.maxErrors {
+		res.Errors = res.Errors[:o.maxErrors]
+		res.Truncated = true
+
+		return res, true
+	}
+
+	if o.maxErrors > 0 && len(res.Errors) == o.maxErrors {
+		// There is no room for the errors of the struct, it is only validated
+		// to know whether there would have been any. Capped so that the
+		// caller's slice is never written to.
+		validated, ok := ValidateStruct(v, append(opts[:len(opts):len(opts)], MaxErrors(1))...)
+		if !ok {
+			res.Errors = append(res.Errors, validated.Errors...)
+			return res, false
+		}
+
+		res.Truncated = len(validated.Errors) > 0
+
+		return res, true
+	}
+
+	if o.maxErrors > 0 {
+		opts = append(opts[:len(opts):len(opts)], MaxErrors(o.maxErrors-len(res.Errors)))
+	}
+
+	validated, ok := ValidateStruct(v, opts...)
+	res.Errors = append(res.Errors, validated.Errors...)
+	res.Truncated = validated.Truncated
+
+	return res, ok
+}
+
+// Same as `Decode` for the JSON in `data`, but every `*FieldError` also has
+// the `Position` of its field in `data`, so that it can be pointed at in an
+// editor. An error about a field that isn't in `data`, like a missing
+// required one, points at the object it should have been in.
+//
+// Ex:
+// res, ok := vx.Unmarshal(body, &filter)
+//
+//	for _, err := range res.Errors {
+//		if fieldErr, ok := err.(*vx.FieldError); ok {
+//			fmt.Println(fieldErr.Position, fieldErr)
+//		}
+//	}
+func Unmarshal(data []byte, v any, opts ...Option) (res VxResult, ok bool) {
+	res, ok = Decode(bytes.NewReader(data), v, opts...)
+	if len(res.Errors) == 0 {
+		return res, ok
+	}
+
+	var positions internal.Positions
+
+	if val := reflect.ValueOf(v); val.Kind() == reflect.Ptr && val.Elem().Kind() == reflect.Struct {
+		schema := schemaFor(val.Elem().Type())
+
+		// The positions found before an error in the JSON are still good.
+		positions, _ = schema.doc.Positions(data)
+	}
+
+	for i, err := range res.Errors {
+		fieldErr, isFieldErr := err.(*FieldError)
+		if !isFieldErr {
+			continue
+		}
+
+		e := *fieldErr
+
+		if offset, found := jsonErrorOffset(e.Err); found {
+			e.Position = positions.FindEnd(data, offset)
+		} else if pos, found := positions.Find(e.Field); found {
+			e.Position = pos
+		}
+
+		res.Errors[i] = &e
+	}
+
+	return res, ok
+}
+
+// The error for a JSON that couldn't be decoded.
+func invalidJSON(err error, o options) *FieldError {
+	fieldErr := &FieldError{Field: "", Path: Path{}, Code: "invalidJSON", Err: fmt.Errorf("invalid JSON document: %w", err)}
+	applyMessage(fieldErr, nil, nil, sameLabel, o)
+
+	return fieldErr
+}
+
+// Gives the offset in the source where encoding/json found the error `err`.
+func jsonErrorOffset(err error) (int, bool) {
+	// The offset is right after the invalid character.
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return int(syntaxErr.Offset) - 1, true
+	}
+
+	// The offset is right after the value of the wrong type.
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return int(typeErr.Offset), true
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return math.MaxInt, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,215 @@
+package vx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type decodeFilter struct {
+	Kind  any `json:"kind" vx:"name=kind, type=string, required"`
+	Limit any `json:"limit" vx:"name=limit, type=int"`
+}
+
+type decodeSearch struct {
+	Query    any            `json:"query" vx:"name=query, type=string, required"`
+	Filter   decodeFilter   `json:"filter" vx:"name=filter"`
+	Sorts    []decodeFilter `json:"sorts" vx:"name=sorts"`
+	Extra    map[string]any `json:"extra" vx:"name=extra"`
+	Anything any            `json:"anything" vx:"name=anything"`
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		opts []Option
+		want want
+	}{
+		{
+			name: "known keys should not give an error",
+			arg:  `{"query": "vx", "filter": {"kind": "a", "limit": 10}, "sorts": [{"kind": "b"}], "extra": {"x": 1}, "anything": {"y": 2}}`,
+			want: want{true, 0},
+		},
+		{
+			name: "unknown keys should give errors, nested ones too",
+			arg:  `{"query": "vx", "filtr": {}, "filter": {"kind": "a", "limitt": 10}, "sorts": [{"kind": "b", "knd": "c"}]}`,
+			want: want{true, 3},
+		},
+		{
+			name: "unknown keys should be reported along with the rules",
+			arg:  `{"querry": "vx", "filter": {"kind": "a"}}`,
+			want: want{true, 2},
+		},
+		{
+			name: "allowed keys should not give an error",
+			arg:  `{"query": "vx", "meta": {"id": 1}, "filter": {"kind": "a", "trace": true}}`,
+			opts: []Option{AllowUnknownFields("meta", "filter.trace")},
+			want: want{true, 0},
+		},
+		{
+			name: "MaxErrors should count the unknown keys",
+			arg:  `{"a": 1, "b": 2, "filter": {}}`,
+			opts: []Option{MaxErrors(1)},
+			want: want{true, 1},
+		},
+		{
+			name: "invalid JSON should give an error",
+			arg:  `{"query": `,
+			want: want{true, 1},
+		},
+		{
+			name: "JSON that doesn't fit the struct should give an error",
+			arg:  `{"filter": []}`,
+			want: want{true, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s decodeSearch
+			res, ok := Decode(strings.NewReader(test.arg), &s, test.opts...)
+
+			if ok != test.want.ok {
+				t.Error(res.String())
+				t.Errorf("expected ok to be %v but got %v, check the errors above", test.want.ok, ok)
+			}
+
+			if len(res.Errors) != test.want.count {
+				t.Error(res.String())
+				t.Errorf("expected to get exactly %v number of validation errors but got %v, check the errors above.", test.want.count, len(res.Errors))
+			}
+		})
+	}
+
+	t.Run("MaxErrors should only truncate when errors are dropped", func(t *testing.T) {
+		tests := []struct {
+			arg       string
+			truncated bool
+		}{
+			{`{"query": "vx", "filter": {"kind": "a"}, "querry": 1}`, false},
+			{`{"filter": {"kind": "a"}, "querry": 1}`, true},
+			{`{"query": "vx", "filter": {"kind": "a"}, "a": 1, "b": 2}`, true},
+		}
+
+		for _, tc := range tests {
+			var s decodeSearch
+			res, _ := Decode(strings.NewReader(tc.arg), &s, MaxErrors(1))
+
+			if len(res.Errors) != 1 || res.Truncated != tc.truncated {
+				t.Errorf("%s: expected 1 error and truncated %v but got %v and %v", tc.arg, tc.truncated, res.Errors, res.Truncated)
+			}
+		}
+	})
+
+	t.Run("unknown key should hint at the closest field", func(t *testing.T) {
+		var s decodeSearch
+		res, _ := Decode(strings.NewReader(`{"query": "vx", "filter": {"kind": "a", "limt": 1}, "zzzzzz": 1}`), &s)
+
+		expected := []string{
+			"filter.limt is not a known field, did you mean limit?",
+			"zzzzzz is not a known field",
+		}
+
+		if strings.Join(res.StringArray(), "\n") != strings.Join(expected, "\n") {
+			t.Errorf("expected %v but got %v", expected, res.StringArray())
+		}
+
+		var fieldErr *FieldError
+		if len(res.Errors) == 0 || !errors.As(res.Errors[0], &fieldErr) || fieldErr.Code != "unknownField" || fieldErr.Field != "filter.limt" {
+			t.Errorf("expected a FieldError for filter.limt but got %v", res.Errors)
+		}
+	})
+
+	t.Run("Decode should fill in the struct", func(t *testing.T) {
+		var s decodeSearch
+		Decode(strings.NewReader(`{"query": "vx", "filter": {"kind": "a"}}`), &s)
+
+		if s.Query != "vx" || s.Filter.Kind != "a" {
+			t.Errorf("expected the struct to be decoded but got %+v", s)
+		}
+	})
+
+	t.Run("Decode should not write into the options of the caller", func(t *testing.T) {
+		opts := make([]Option, 1, 2)
+		opts[0] = MaxErrors(5)
+
+		spare := FirstErrorPerField()
+		opts = append(opts, spare)[:1]
+
+		var s decodeSearch
+		Decode(strings.NewReader(`{"query": "vx", "filter": {"kind": "a"}}`), &s, opts...)
+
+		o := makeOptions(opts[:2])
+		if !o.firstErrorPerField || o.maxErrors != 5 {
+			t.Errorf("expected the spare option to stay but got %+v", o)
+		}
+	})
+
+	t.Run("Decode without a pointer should give an internal error", func(t *testing.T) {
+		res, ok := Decode(strings.NewReader(`{}`), decodeSearch{})
+		if ok || len(res.Errors) != 1 {
+			t.Errorf("expected a single internal error but got %v, %v", ok, res.StringArray())
+		}
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := `{
+  "query": 42,
+  "filter": {
+    "kind": "a",
+    "limitt": 10
+  },
+  "sorts": [{"kind": "b"}]
+}`
+
+	tests := []struct {
+		name string
+		arg  string
+		want []string
+	}{
+		{
+			name: "errors should point at the values and the unknown keys",
+			arg:  data,
+			want: []string{"filter.limitt 5:5", "query 2:12"},
+		},
+		{
+			name: "missing field should point at its object",
+			arg:  `{"query": "vx", "filter": {"limit": 1}}`,
+			want: []string{"filter.kind 1:27"},
+		},
+		{
+			name: "invalid JSON should point at the error",
+			arg:  "{\n  \"query\": \"vx\",\n  \"filter\": }",
+			want: []string{" 3:13"},
+		},
+		{
+			name: "value of the wrong type for the struct should point at it",
+			arg:  `{"query": "vx", "filter": "a"}`,
+			want: []string{" 1:27"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s decodeSearch
+			res, _ := Unmarshal([]byte(test.arg), &s)
+
+			got := []string{}
+			for _, err := range res.Errors {
+				var fieldErr *FieldError
+				if !errors.As(err, &fieldErr) {
+					t.Fatalf("expected a FieldError but got %v", err)
+				}
+
+				got = append(got, fieldErr.Field+" "+fieldErr.Position.String())
+			}
+
+			if strings.Join(got, ", ") != strings.Join(test.want, ", ") {
+				t.Error(res.String())
+				t.Errorf("expected %v but got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -1,3 +1,3 @@
 module vx
 
-go 1.19
+go 1.20
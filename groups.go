@@ -0,0 +1,117 @@
+package vx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"vx/internal"
+)
+
+// A constraint on a group of fields of a struct as a whole. The errors for it
+// are reported on the struct, not on any of the fields in the group.
+//
+// It can be put in the "vx" tag of a blank `_` field of the struct:
+//
+//	type search struct {
+//		_     struct{} `vx:"exclusive=assoc bonus, atLeastOne=name email id"`
+//		Assoc any      `vx:"name=assoc"`
+//		...
+//	}
+//
+// or registered with `RegisterFieldGroup`. A field counts as present when it's
+// not missing like for "required", so 0 and false are present too.
+type FieldGroup string
+
+const (
+	// At most one of the fields can be present.
+	Exclusive FieldGroup = "exclusive"
+	// At least one of the fields must be present.
+	AtLeastOne FieldGroup = "atLeastOne"
+	// Exactly one of the fields must be present.
+	ExactlyOne FieldGroup = "exactlyOne"
+	// Either all of the fields must be present or none of them.
+	AllOrNone FieldGroup = "allOrNone"
+)
+
+var registeredFieldGroups = struct {
+	sync.RWMutex
+	// Same as the tags on `_` fields, by the type of the struct.
+	tagStrings map[reflect.Type][]string
+}{
+	tagStrings: map[reflect.Type][]string{},
+}
+
+// Registers a field group on the struct `T`, same as having it in the tag of
+// a blank `_` field. Useful for the structs that can't be changed, like the
+// ones from another package. `fields` are the names of the fields in `T`.
+// Registering the same group again does nothing.
+//
+// Ex:
+// vx.RegisterFieldGroup[search](vx.AtLeastOne, "name", "email", "id")
+func RegisterFieldGroup[T any](group FieldGroup, fields ...string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	registeredFieldGroups.Lock()
+	defer registeredFieldGroups.Unlock()
+
+	tagString := fmt.Sprintf("%s=%s", group, strings.Join(fields, " "))
+	if contains(registeredFieldGroups.tagStrings[t], tagString) {
+		return
+	}
+
+	registeredFieldGroups.tagStrings[t] = append(registeredFieldGroups.tagStrings[t], tagString)
+}
+
+// Removes all the field groups registered with `RegisterFieldGroup`, like for
+// a test to start from none. The ones in the tags stay.
+func ResetFieldGroups() {
+	registeredFieldGroups.Lock()
+	defer registeredFieldGroups.Unlock()
+
+	registeredFieldGroups.tagStrings = map[reflect.Type][]string{}
+}
+
+// Makes the field groups of a struct from the tags on its `_` fields and the
+// ones registered for its type.
+func makeFieldGroups(info internal.VxStructInfo) ([]internal.FieldGroup, error) {
+	tagStrings := append([]string{}, info.TagStrings...)
+
+	registeredFieldGroups.RLock()
+	tagStrings = append(tagStrings, registeredFieldGroups.tagStrings[info.Type]...)
+	registeredFieldGroups.RUnlock()
+
+	groups := []internal.FieldGroup{}
+
+	for _, tagString := range tagStrings {
+		g, err := internal.MakeFieldGroups(tagString)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", info.Type, err.Error())
+		}
+
+		groups = append(groups, g...)
+	}
+
+	return groups, nil
+}
+
+// Finds the fields in a group of the struct named `structName`.
+func resolveFieldGroup(structName string, group internal.FieldGroup, fields map[string]internal.VxField) ([]internal.VxField, error) {
+	prefix := ""
+	if structName != "" {
+		prefix = structName + "."
+	}
+
+	refs := []internal.VxField{}
+
+	for _, ref := range group.Refs {
+		field, ok := lookupField(prefix+ref, fields)
+		if !ok {
+			return nil, fmt.Errorf("%s: refers to an unknown field %s", group.Key, prefix+ref)
+		}
+
+		refs = append(refs, field)
+	}
+
+	return refs, nil
+}
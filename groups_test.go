@@ -0,0 +1,126 @@
+package vx
+
+import (
+	"testing"
+)
+
+type assocOrBonus struct {
+	_     struct{} `vx:"exclusive=assoc bonus"`
+	Assoc any      `vx:"name=assoc"`
+	Bonus any      `vx:"name=bonus"`
+}
+
+type search struct {
+	Name  any `vx:"name=name"`
+	Email any `vx:"name=email"`
+	ID    any `vx:"name=id"`
+}
+
+type dateRange struct {
+	_    struct{} `vx:"allOrNone=from to"`
+	From any      `vx:"name=from"`
+	To   any      `vx:"name=to"`
+}
+
+type listFilter struct {
+	_       struct{} `vx:"exactlyOne=kind Filter.assoc Filter.bonus"`
+	Kind    any      `vx:"name=kind"`
+	Filter  assocOrBonus
+	Created dateRange
+}
+
+func TestFieldGroup(t *testing.T) {
+	RegisterFieldGroup[search](AtLeastOne, "name", "email", "id")
+	RegisterFieldGroup[search](AtLeastOne, "name", "email", "id")
+	t.Cleanup(ResetFieldGroups)
+
+	type unknownField struct {
+		_ struct{} `vx:"exclusive=a b"`
+		A any
+	}
+
+	type invalidGroup struct {
+		_ struct{} `vx:"exclusive=a, oneOf=a b"`
+		A any
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "assocOrBonus with only assoc should not give an error",
+			arg:  assocOrBonus{Assoc: "a"},
+			want: want{true, 0},
+		},
+		{
+			name: "assocOrBonus with both assoc and bonus should give an error",
+			arg:  assocOrBonus{Assoc: "a", Bonus: "b"},
+			want: want{true, 1},
+		},
+		{
+			name: "search with id 0 should not give an error",
+			arg:  search{ID: 0},
+			want: want{true, 0},
+		},
+		{
+			name: "search without anything should give an error",
+			arg:  search{Name: ""},
+			want: want{true, 1},
+		},
+		{
+			name: "listFilter with kind and a full range should not give an error",
+			arg: listFilter{
+				Kind:    "assoc",
+				Created: dateRange{From: "2022-01-01", To: "2022-12-31"},
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "listFilter with kind, assoc, bonus and half a range should give errors",
+			arg: listFilter{
+				Kind:    "assoc",
+				Filter:  assocOrBonus{Assoc: "a", Bonus: "b"},
+				Created: dateRange{From: "2022-01-01"},
+			},
+			want: want{true, 3},
+		},
+		{
+			name: "unknownField should give an internal error",
+			arg:  unknownField{},
+			want: want{false, 1},
+		},
+		{
+			name: "invalidGroup should give an internal error",
+			arg:  invalidGroup{},
+			want: want{false, 1},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+
+	t.Run("errors should be reported on the struct", func(t *testing.T) {
+		res, _ := ValidateStruct(listFilter{
+			Filter:  assocOrBonus{Assoc: "a", Bonus: "b"},
+			Created: dateRange{To: "2022-12-31"},
+		})
+
+		expected := map[string]string{
+			"":        "exactlyOne",
+			"Filter":  "exclusive",
+			"Created": "allOrNone",
+		}
+
+		if len(res.Errors) != len(expected) {
+			t.Fatalf("expected %d errors but got %d: %s", len(expected), len(res.Errors), res.String())
+		}
+
+		for _, err := range res.Errors {
+			fieldErr, ok := err.(*FieldError)
+			if !ok {
+				t.Fatalf("expected a *FieldError but got %T", err)
+			}
+
+			if code, ok := expected[fieldErr.Field]; !ok || code != fieldErr.Code {
+				t.Errorf("unexpected error %q with code %q on %q", fieldErr, fieldErr.Code, fieldErr.Field)
+			}
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Wrapped by the error we give when a number with a fractional part is given
+// to a field which expects an integer.
+var ErrNotWholeNumber = errors.New("must be a whole number")
+
+// Converts JSON numbers to the numeric type `t`.
+//
+// When decoding into `any`, encoding/json gives a float64 for every number (or
+// a json.Number with `UseNumber`), so something like `42` would never match
+// "type=int" as it is. Containers that encoding/json would make, `[]any` and
+// `map[string]any`, are converted elem by elem when `t` is a slice, array or
+// map of numbers.
+//
+// Returns `ok` as false when there was nothing to convert, i.e. `v` isn't a
+// JSON number (or a container of them) or `t` isn't numeric. The caller should
+// go on with its usual type checks in that case. `typeName` names `t` in the
+// errors.
+func ConvertNumber(name string, v any, t reflect.Type, typeName TypeNamer) (converted any, ok bool, err error) {
+	if !hasNumbers(t) {
+		return nil, false, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		s, isSlice := v.([]any)
+		if !isSlice {
+			return nil, false, nil
+		}
+
+		out := reflect.MakeSlice(t, len(s), len(s))
+		for i, elem := range s {
+			c, ok, err := convertElem(fmt.Sprintf("%s[%d]", name, i), elem, t.Elem(), typeName)
+			if !ok || err != nil {
+				return nil, ok, err
+			}
+
+			out.Index(i).Set(c)
+		}
+
+		return out.Interface(), true, nil
+	case reflect.Array:
+		s, isSlice := v.([]any)
+		if !isSlice || len(s) != t.Len() {
+			return nil, false, nil
+		}
+
+		out := reflect.New(t).Elem()
+		for i, elem := range s {
+			c, ok, err := convertElem(fmt.Sprintf("%s[%d]", name, i), elem, t.Elem(), typeName)
+			if !ok || err != nil {
+				return nil, ok, err
+			}
+
+			out.Index(i).Set(c)
+		}
+
+		return out.Interface(), true, nil
+	case reflect.Map:
+		m, isMap := v.(map[string]any)
+		if !isMap || t.Key().Kind() != reflect.String {
+			return nil, false, nil
+		}
+
+		out := reflect.MakeMapWithSize(t, len(m))
+		for key, elem := range m {
+			c, ok, err := convertElem(fmt.Sprintf("%s[%s]", name, key), elem, t.Elem(), typeName)
+			if !ok || err != nil {
+				return nil, ok, err
+			}
+
+			out.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), c)
+		}
+
+		return out.Interface(), true, nil
+	}
+
+	var f float64
+
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case json.Number:
+		if isInteger(t.Kind()) {
+			// Go through `Int64` first so that big integers don't lose
+			// precision on the way through float64.
+			if i, err := n.Int64(); err == nil {
+				out := reflect.New(t).Elem()
+				if !setInteger(out, i) {
+					return nil, true, fmt.Errorf("%s is out of range for type %s, got %v", name, typeName(t), v)
+				}
+
+				return out.Interface(), true, nil
+			}
+		}
+
+		f, err = n.Float64()
+		if err != nil {
+			return nil, false, nil
+		}
+	default:
+		return nil, false, nil
+	}
+
+	out := reflect.New(t).Elem()
+
+	if isInteger(t.Kind()) {
+		if f != math.Trunc(f) {
+			return nil, true, fmt.Errorf("%s %w but got %v", name, ErrNotWholeNumber, v)
+		}
+
+		if f < math.MinInt64 || f >= math.MaxInt64 || !setInteger(out, int64(f)) {
+			return nil, true, fmt.Errorf("%s is out of range for type %s, got %v", name, typeName(t), v)
+		}
+
+		return out.Interface(), true, nil
+	}
+
+	if out.OverflowFloat(f) {
+		return nil, true, fmt.Errorf("%s is out of range for type %s, got %v", name, typeName(t), v)
+	}
+
+	out.SetFloat(f)
+
+	return out.Interface(), true, nil
+}
+
+// Converts a single elem of a container for `ConvertNumber`. Elems which are
+// not numbers are kept as they are as long as they fit in `t`.
+func convertElem(name string, elem any, t reflect.Type, typeName TypeNamer) (reflect.Value, bool, error) {
+	c, ok, err := ConvertNumber(name, elem, t, typeName)
+	if err != nil {
+		return reflect.Value{}, true, err
+	}
+
+	if ok {
+		return reflect.ValueOf(c), true, nil
+	}
+
+	v := reflect.ValueOf(elem)
+	if !v.IsValid() || !v.Type().AssignableTo(t) {
+		return reflect.Value{}, false, nil
+	}
+
+	return v, true, nil
+}
+
+// Sets `i` on the integer value `v`. Returns false if `i` doesn't fit in it.
+func setInteger(v reflect.Value, i int64) bool {
+	switch {
+	case v.CanInt():
+		if v.OverflowInt(i) {
+			return false
+		}
+
+		v.SetInt(i)
+	case v.CanUint():
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return false
+		}
+
+		v.SetUint(uint64(i))
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Whether `t` is a number or a container which has numbers in it.
+func hasNumbers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasNumbers(t.Elem())
+	case reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return isInteger(t.Kind())
+}
+
+func isInteger(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+// Parses strings into the type `t`, for the values which only ever come as
+// strings like query params, form values or CSV columns. "42", "true" and
+// "2.5" can be parsed into `int`, `bool` and `float64` respectively. Slices
+// and arrays are parsed elem by elem from `[]string` or `[]any` of strings,
+// like the values of `url.Values`.
+//
+// Returns `ok` as false when there was nothing to parse, i.e. `v` isn't a
+// string (or a container of them) or `t` can't be parsed from a string.
+// `typeName` names `t` in the errors.
+func CoerceString(name string, v any, t reflect.Type, typeName TypeNamer) (converted any, ok bool, err error) {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		var elems []any
+
+		switch s := v.(type) {
+		case []string:
+			for _, elem := range s {
+				elems = append(elems, elem)
+			}
+		case []any:
+			elems = s
+		default:
+			return nil, false, nil
+		}
+
+		var out reflect.Value
+		if t.Kind() == reflect.Slice {
+			out = reflect.MakeSlice(t, len(elems), len(elems))
+		} else if len(elems) == t.Len() {
+			out = reflect.New(t).Elem()
+		} else {
+			return nil, false, nil
+		}
+
+		for i, elem := range elems {
+			elemName := fmt.Sprintf("%s[%d]", name, i)
+
+			c, ok, err := CoerceString(elemName, elem, t.Elem(), typeName)
+			if err != nil {
+				return nil, true, err
+			}
+
+			if !ok {
+				v := reflect.ValueOf(elem)
+				if !v.IsValid() || !v.Type().AssignableTo(t.Elem()) {
+					return nil, false, nil
+				}
+
+				c = elem
+			}
+
+			out.Index(i).Set(reflect.ValueOf(c))
+		}
+
+		return out.Interface(), true, nil
+	}
+
+	s, isString := v.(string)
+	if !isString {
+		return nil, false, nil
+	}
+
+	s = strings.TrimSpace(s)
+	out := reflect.New(t).Elem()
+
+	switch {
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, true, fmt.Errorf("%s should be of type %s but got %q", name, typeName(t), v)
+		}
+
+		out.SetBool(b)
+	case isInteger(t.Kind()):
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			if !setInteger(out, i) {
+				return nil, true, fmt.Errorf("%s is out of range for type %s, got %v", name, typeName(t), v)
+			}
+
+			break
+		}
+
+		// Something like "2.0" or "2.5", let `ConvertNumber` decide if it's
+		// a whole number or not.
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, true, fmt.Errorf("%s should be of type %s but got %q", name, typeName(t), v)
+		}
+
+		return ConvertNumber(name, f, t, typeName)
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return nil, true, fmt.Errorf("%s should be of type %s but got %q", name, typeName(t), v)
+		}
+
+		out.SetFloat(f)
+	default:
+		return nil, false, nil
+	}
+
+	return out.Interface(), true, nil
+}
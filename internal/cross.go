@@ -0,0 +1,251 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Implemented by the rules which look at other fields of the struct as well,
+// like "eqField=password".
+//
+// `Refs` are the other fields as they were written in the tag. They are
+// resolved by the caller who then gives them to `Bind`, in the same order, to
+// get the rule that can actually be executed.
+type CrossFieldRule interface {
+	rule
+	Refs() []string
+	Bind(refs []VxField) rule
+}
+
+//
+// Rules comparing a field with another field.
+//
+
+type compareField struct {
+	// Key of the rule in the tag, like "eqField".
+	key string
+	ref string
+	// The field that `ref` points to, set by `Bind`.
+	other *VxField
+}
+
+func makeCompareField(key string, ref string) (compareField, error) {
+	if ref == "" {
+		return compareField{}, fmt.Errorf("%s: should have the name of another field", key)
+	}
+
+	return compareField{key: key, ref: ref}, nil
+}
+
+func (r compareField) Refs() []string {
+	return []string{r.ref}
+}
+
+func (r compareField) Bind(refs []VxField) rule {
+	r.other = &refs[0]
+	return r
+}
+
+func (r compareField) Exec(field VxField) error {
+	if r.other == nil {
+		return fmt.Errorf("%s - %s: %s was never resolved", field.DisplayName(), r.key, r.ref)
+	}
+
+	// It's the job of "required" to complain about missing values.
+	if field.Value == nil || r.other.Value == nil {
+		return nil
+	}
+
+	params := map[string]any{"other": r.other.Name}
+
+	switch r.key {
+	case "eqField":
+		if !equal(field.Value, r.other.Value) {
+			return WithParams(fmt.Errorf("%s must be equal to %s", field.DisplayName(), r.other.DisplayName()), params)
+		}
+
+		return nil
+	case "neField":
+		if equal(field.Value, r.other.Value) {
+			return WithParams(fmt.Errorf("%s must not be equal to %s", field.DisplayName(), r.other.DisplayName()), params)
+		}
+
+		return nil
+	}
+
+	c, ok := compare(field.Value, r.other.Value)
+	if !ok {
+		return fmt.Errorf("%s - %s: can't compare type %s with %s of type %s", field.DisplayName(), r.key, field.ValueType, r.other.DisplayName(), r.other.ValueType)
+	}
+
+	switch {
+	case r.key == "gtField" && c <= 0:
+		return WithParams(fmt.Errorf("%s must be greater than %s", field.DisplayName(), r.other.DisplayName()), params)
+	case r.key == "gteField" && c < 0:
+		return WithParams(fmt.Errorf("%s must be greater than or equal to %s", field.DisplayName(), r.other.DisplayName()), params)
+	case r.key == "ltField" && c >= 0:
+		return WithParams(fmt.Errorf("%s must be less than %s", field.DisplayName(), r.other.DisplayName()), params)
+	case r.key == "lteField" && c > 0:
+		return WithParams(fmt.Errorf("%s must be less than or equal to %s", field.DisplayName(), r.other.DisplayName()), params)
+	}
+
+	return nil
+}
+
+// Whether `a` and `b` are equal. Numbers are equal if they have the same value
+// whatever their types are, so `1` from Go equals `1.0` from JSON.
+func equal(a, b any) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// Compares two numbers, strings or `time.Time`s. Strings are compared byte by
+// byte, which works for dates in the same ISO 8601 format, like "2006-01-02".
+//
+// Returns -1, 0 or +1 like `strings.Compare` and false if `a` and `b` can't
+// be compared.
+func compare(a, b any) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		return strings.Compare(x, y), ok
+	case time.Time:
+		y, ok := b.(time.Time)
+
+		switch {
+		case !ok:
+			return 0, false
+		case x.Before(y):
+			return -1, true
+		case x.After(y):
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// Gives the value of any Go number or json.Number as a float64.
+func toFloat(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
+
+//
+// Rules requiring a field depending on other fields.
+//
+
+// What counts as missing is the same as for "required". A field "is present"
+// for "requiredWith" and "requiredWithout" when it's not missing, where 0 and
+// false are present too.
+type requiredIf struct {
+	// Key of the rule in the tag, like "requiredIf".
+	key  string
+	refs []string
+	// The value to compare with for "requiredIf" and "requiredUnless".
+	value string
+	// The fields that `refs` point to, set by `Bind`.
+	others []VxField
+}
+
+func makeRequiredIf(key string, v string) (requiredIf, error) {
+	splits := strings.Fields(v)
+
+	switch key {
+	case "requiredIf", "requiredUnless":
+		if len(splits) < 2 {
+			return requiredIf{}, fmt.Errorf("%s: should have the name of another field and a value, like `%s=kind bonus`, got %s", key, key, v)
+		}
+
+		return requiredIf{key: key, refs: splits[:1], value: strings.Join(splits[1:], " ")}, nil
+	}
+
+	if len(splits) == 0 {
+		return requiredIf{}, fmt.Errorf("%s: should have the names of other fields, like `%s=email phone`", key, key)
+	}
+
+	return requiredIf{key: key, refs: splits}, nil
+}
+
+func (r requiredIf) Refs() []string {
+	return r.refs
+}
+
+func (r requiredIf) Bind(refs []VxField) rule {
+	r.others = refs
+	return r
+}
+
+func (r requiredIf) Exec(field VxField) error {
+	if len(r.others) != len(r.refs) {
+		return fmt.Errorf("%s - %s: %s was never resolved", field.DisplayName(), r.key, strings.Join(r.refs, " "))
+	}
+
+	if !isMissing(field.Value, false) {
+		return nil
+	}
+
+	switch r.key {
+	case "requiredIf":
+		if other := r.others[0]; other.Value != nil && fmt.Sprint(other.Value) == r.value {
+			return WithParams(fmt.Errorf("%s is required when %s is %s", field.DisplayName(), other.DisplayName(), r.value), map[string]any{"other": other.Name, "when": r.value})
+		}
+	case "requiredUnless":
+		if other := r.others[0]; other.Value == nil || fmt.Sprint(other.Value) != r.value {
+			return WithParams(fmt.Errorf("%s is required unless %s is %s", field.DisplayName(), other.DisplayName(), r.value), map[string]any{"other": other.Name, "when": r.value})
+		}
+	case "requiredWith":
+		for _, other := range r.others {
+			if !isMissing(other.Value, false) {
+				return WithParams(fmt.Errorf("%s is required when %s is present", field.DisplayName(), other.DisplayName()), map[string]any{"other": other.Name})
+			}
+		}
+	case "requiredWithout":
+		for _, other := range r.others {
+			if isMissing(other.Value, false) {
+				return WithParams(fmt.Errorf("%s is required when %s is absent", field.DisplayName(), other.DisplayName()), map[string]any{"other": other.Name})
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,363 @@
+package internal
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	anyType             = reflect.TypeOf((*any)(nil)).Elem()
+	objectType          = reflect.TypeOf(map[string]any{})
+	optionalType        = reflect.TypeOf((*Optional)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// The fields of a struct type the way they are in a JSON document, so that
+// the document can be validated against the tags of the struct without being
+// decoded into it first.
+type DocSchema struct {
+	// Type of the struct.
+	Type reflect.Type
+	// The fields of the struct, except the blank, unexported and `json:"-"`
+	// ones.
+	Fields []DocField
+	// The "vx" tags on the blank `_` fields of the struct.
+	TagStrings []string
+}
+
+type DocField struct {
+	// Name of the field in the errors, same as `VxField.Name`.
+	Name string
+	// Key of the field in a `Path`.
+	PathKey string
+	// Key of the field in the document, the name in the "json" tag or the
+	// name of the field in the struct.
+	Key string
+	// The `"vx"` tag on the field.
+	TagString string
+	// Type of the field in the struct, of the value inside for an `Optional`.
+	Type reflect.Type
+	// Type the value should have in the document, which is `Type` with the
+	// structs as `map[string]any`, like encoding/json would decode them.
+	DocType       reflect.Type
+	IsOptional    bool
+	IsOptionalPtr bool
+	// Whether the value has to be decoded into `DocType` with encoding/json,
+	// because something in it has its own way to be decoded, like
+	// `time.Time`.
+	Decode bool
+	// Schema of the struct for a nested struct, or of the elem for a slice
+	// or array of structs.
+	Struct *DocSchema
+	// Whether `Struct` is the schema of the elems of a slice or array.
+	IsList bool
+	// Whether the field is an embedded struct without a name in the "json"
+	// tag, whose fields are then in the same object as its parent's.
+	Embedded bool
+}
+
+// Makes the `DocSchema` of the struct type `t`. The tags of the fields are
+// made once here against the types in the struct, and their errors returned.
+func MakeDocSchema(t reflect.Type) (*DocSchema, []error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, []error{fmt.Errorf("expected struct, received %s", t.Kind().String())}
+	}
+
+	errs := []error{}
+	schema := makeDocSchema(t, map[reflect.Type]*DocSchema{}, &errs)
+
+	return schema, errs
+}
+
+// Does the actual work for `MakeDocSchema`. The schemas already made are in
+// `seen`, for the structs which have themselves in them, like a tree.
+func makeDocSchema(t reflect.Type, seen map[reflect.Type]*DocSchema, errs *[]error) *DocSchema {
+	if schema, ok := seen[t]; ok {
+		return schema
+	}
+
+	schema := &DocSchema{Type: t, Fields: []DocField{}, TagStrings: []string{}}
+	seen[t] = schema
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Name == "_" {
+			if tagString, ok := field.Tag.Lookup(VX_TAG_KEY); ok {
+				schema.TagStrings = append(schema.TagStrings, tagString)
+			}
+
+			continue
+		}
+
+		key, hasKey, omit := jsonKey(field)
+		if omit || !field.IsExported() {
+			continue
+		}
+
+		tagString := field.Tag.Get(VX_TAG_KEY)
+		docField := DocField{
+			Name:      tagName(tagString, field.Name),
+			PathKey:   pathKey(field, tagString),
+			Key:       key,
+			TagString: tagString,
+			Type:      field.Type,
+		}
+
+		if isOptional(field.Type) {
+			docField.Type = optionalValueType(field.Type)
+			docField.IsOptional = true
+			docField.IsOptionalPtr = field.Type.Kind() == reflect.Ptr
+		}
+
+		// Checking the tag against the type in the struct, there is only `any`
+		// in the documents to check it against later.
+		if _, err := MakeTag(VxField{Name: docField.Name, Type: docField.Type, TagString: tagString, IsOptionalPtr: docField.IsOptionalPtr}); err != nil {
+			*errs = append(*errs, err)
+		}
+
+		docField.DocType = docType(docField.Type)
+		docField.Decode = hasUnmarshaler(docField.DocType)
+
+		elem := docField.Type
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+
+		switch {
+		case isObject(elem):
+			docField.Struct = makeDocSchema(elem, seen, errs)
+			docField.Embedded = field.Anonymous && !hasKey
+		case (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && isObject(elem.Elem()):
+			docField.Struct = makeDocSchema(elem.Elem(), seen, errs)
+			docField.IsList = true
+		}
+
+		schema.Fields = append(schema.Fields, docField)
+	}
+
+	return schema
+}
+
+// Gives the key of the field in a JSON document the same way encoding/json
+// does, whether it was named in the "json" tag and whether it's left out.
+func jsonKey(field reflect.StructField) (key string, hasKey bool, omit bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name, false, false
+	}
+
+	return name, true, false
+}
+
+// Whether `t` is a struct that is an object in a document, and not something
+// like `time.Time` which decodes itself from something else.
+func isObject(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !isUnmarshaler(t)
+}
+
+func isUnmarshaler(t reflect.Type) bool {
+	p := reflect.PointerTo(t)
+	return p.Implements(jsonUnmarshalerType) || p.Implements(textUnmarshalerType)
+}
+
+// Gives the type that encoding/json would decode a value of type `t` into,
+// if it was decoding into `any`, as long as `t` isn't an `isUnmarshaler`.
+// Structs become `map[string]any`, pointers their elem and named types like
+// `type kind string` the type they are made of.
+func docType(t reflect.Type) reflect.Type {
+	if isUnmarshaler(t) {
+		return t
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return docType(t.Elem())
+	case reflect.Struct:
+		return objectType
+	case reflect.Interface:
+		return anyType
+	case reflect.Slice:
+		// Bytes are a base64 string in JSON.
+		if t.Elem().Kind() == reflect.Uint8 {
+			return reflect.TypeOf("")
+		}
+
+		return reflect.SliceOf(docType(t.Elem()))
+	case reflect.Array:
+		return reflect.ArrayOf(t.Len(), docType(t.Elem()))
+	case reflect.Map:
+		return reflect.MapOf(docType(t.Key()), docType(t.Elem()))
+	case reflect.Bool:
+		return reflect.TypeOf(false)
+	case reflect.String:
+		return reflect.TypeOf("")
+	}
+
+	if basic, ok := basicTypes[t.Kind()]; ok {
+		return basic
+	}
+
+	return t
+}
+
+// The unnamed number types, by their kind.
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+// Whether there is an `isUnmarshaler` anywhere in the type `t`.
+func hasUnmarshaler(t reflect.Type) bool {
+	if isUnmarshaler(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return hasUnmarshaler(t.Elem())
+	case reflect.Map:
+		return hasUnmarshaler(t.Key()) || hasUnmarshaler(t.Elem())
+	}
+
+	return false
+}
+
+// Makes a `VxStruct` out of the document `m`, like `ParseStruct` does out of a
+// struct. The fields are all `any` with their `SchemaType` set, and there are
+// no values of the structs to call `Validator`s on.
+//
+// Nested objects are parsed as nested structs, and the objects in an array as
+// nested structs named after their index, like "items[0].price".
+func (s *DocSchema) Parse(m map[string]any) VxStruct {
+	fields, structs := s.parse("", Path{}, m)
+
+	return VxStruct{
+		Name:    s.Type.Name(),
+		Fields:  fields,
+		Structs: structs,
+	}
+}
+
+func (s *DocSchema) parse(prefix string, path Path, m map[string]any) ([]VxField, []VxStructInfo) {
+	fields := []VxField{}
+	structs := []VxStructInfo{{Name: strings.TrimSuffix(prefix, "."), Type: s.Type, TagStrings: s.TagStrings, Path: path}}
+
+	add := func(f []VxField, s []VxStructInfo) {
+		fields = append(fields, f...)
+		structs = append(structs, s...)
+	}
+
+	for _, f := range s.Fields {
+		Name := prefix + f.Name
+		Path := path.Key(f.PathKey)
+
+		if f.Embedded {
+			// The fields of an embedded struct are in the same object, so
+			// are their paths.
+			add(f.Struct.parse(Name+".", path, m))
+			continue
+		}
+
+		Value, found := lookupKey(m, f.Key)
+		if f.Decode && Value != nil {
+			Value = decode(Value, f.DocType)
+		}
+
+		if f.Struct != nil {
+			switch obj, isObj := Value.(map[string]any); {
+			case f.IsList:
+				list, _ := Value.([]any)
+				for i, elem := range list {
+					if obj, isObj := elem.(map[string]any); isObj {
+						add(f.Struct.parse(fmt.Sprintf("%s[%d].", Name, i), Path.Index(i), obj))
+					}
+				}
+			case isObj:
+				add(f.Struct.parse(Name+".", Path, obj))
+			case Value == nil && f.Type.Kind() != reflect.Ptr:
+				// Same as a zero struct, its fields are still checked.
+				add(f.Struct.parse(Name+".", Path, nil))
+			}
+		}
+
+		Present := Value != nil
+		Null := false
+
+		if f.IsOptional {
+			Present = found
+			Null = found && Value == nil
+		}
+
+		fields = append(fields, VxField{
+			Name:          Name,
+			Type:          anyType,
+			TagString:     f.TagString,
+			Value:         Value,
+			ValueType:     reflect.TypeOf(Value),
+			IsOptional:    f.IsOptional,
+			IsOptionalPtr: f.IsOptionalPtr,
+			Present:       Present,
+			Null:          Null,
+			SchemaType:    f.DocType,
+			Path:          Path,
+		})
+	}
+
+	return fields, structs
+}
+
+// Finds the `key` in the object `m`, ignoring the case if it's not there as
+// it is, like encoding/json does.
+func lookupKey(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// Decodes `v` into the type `t` with encoding/json. Gives back `v` as it is
+// if it can't be decoded, the type check will then complain about it.
+func decode(v any, t reflect.Type) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	out := reflect.New(t)
+	if err := json.Unmarshal(data, out.Interface()); err != nil {
+		return v
+	}
+
+	return out.Elem().Interface()
+}
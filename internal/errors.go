@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// An error found during the validation along with where it was found.
+type FieldError struct {
+	// Name of the field, like "ComplexA.simple_a". For the errors about a
+	// struct as a whole, it's the name of the struct, "" for the root struct.
+	Field string
+	// Where the field is from the root, which unlike `Field` can be rendered
+	// in any notation, like a JSON Pointer.
+	Path Path
+	// The rule that failed, like "required" or "exclusive".
+	Code string
+	// What the rule was checked with, like the fields of "exclusive". Nil if
+	// there is nothing more to it than the `Code`.
+	Params map[string]any
+	// What went wrong.
+	Err error
+	// Where the field is in the JSON it was decoded from, only set by
+	// `vx.Unmarshal`.
+	Position Position
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// An error of a rule along with what the rule was checked with, like the
+// limit of "minLength". They end up in `FieldError.Params`.
+type RuleError struct {
+	Err    error
+	Params map[string]any
+}
+
+func (e *RuleError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// Gives `err` along with the `params` as a `*RuleError`.
+func WithParams(err error, params map[string]any) error {
+	return &RuleError{Err: err, Params: params}
+}
+
+// How a `FieldError` looks in JSON, the message is what `Error` gives. See
+// `vx.FieldError` for the keys.
+type fieldErrorJSON struct {
+	Field    string         `json:"field"`
+	Path     Path           `json:"path"`
+	Code     string         `json:"code"`
+	Message  string         `json:"message"`
+	Params   map[string]any `json:"params,omitempty"`
+	Position *Position      `json:"position,omitempty"`
+}
+
+func (e FieldError) MarshalJSON() ([]byte, error) {
+	j := fieldErrorJSON{
+		Field:   e.Field,
+		Path:    e.Path,
+		Code:    e.Code,
+		Message: e.Error(),
+		Params:  e.Params,
+	}
+
+	if e.Position.IsValid() {
+		j.Position = &e.Position
+	}
+
+	return json.Marshal(j)
+}
+
+// Only the message of `Err` can be brought back, as a new error. Numbers in
+// `Params` come back as float64, like anything else decoded into `any`.
+func (e *FieldError) UnmarshalJSON(data []byte) error {
+	var j fieldErrorJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*e = FieldError{
+		Field:  j.Field,
+		Path:   j.Path,
+		Code:   j.Code,
+		Params: j.Params,
+		Err:    errors.New(j.Message),
+	}
+
+	if j.Position != nil {
+		e.Position = *j.Position
+	}
+
+	return nil
+}
+
+// Where something is in a JSON source.
+type Position struct {
+	// Bytes from the start of the source, starting at 0.
+	Offset int `json:"offset"`
+	// Line and column, both starting at 1. The column is in characters, not
+	// in bytes.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Whether the position was set at all.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
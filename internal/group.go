@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A constraint on a group of fields of a struct, from the "vx" tag on a blank
+// `_` field of the struct.
+//
+// Ex:
+//
+//	type search struct {
+//		_     struct{} `vx:"exclusive=assoc bonus, atLeastOne=name email id"`
+//		Assoc any      `vx:"name=assoc"`
+//		...
+//	}
+type FieldGroup struct {
+	// Key of the constraint in the tag, like "exclusive".
+	Key string
+	// Names of the fields in the group, relative to the struct.
+	Refs []string
+	// Validation groups of the constraint, like "create" for
+	// "exclusive@create=assoc bonus". Without any, it always applies.
+	Groups []string
+}
+
+func MakeFieldGroups(tagString string) ([]FieldGroup, error) {
+	fieldGroups := []FieldGroup{}
+
+	for _, split := range strings.Split(tagString, ",") {
+		key, v, groups := splitTagItem(split)
+
+		switch key {
+		case "":
+			// Nothing here, like a trailing ",".
+		case "exclusive", "atLeastOne", "exactlyOne", "allOrNone":
+			refs := strings.Fields(v)
+			if len(refs) < 2 {
+				return nil, fmt.Errorf("%s: should have the names of at least 2 fields, like `%s=email phone`, got %s", key, key, v)
+			}
+
+			fieldGroups = append(fieldGroups, FieldGroup{Key: key, Refs: refs, Groups: groups})
+		default:
+			return nil, fmt.Errorf("got an invalid value `%s` in the tag of a `_` field, it can only have exclusive, atLeastOne, exactlyOne and allOrNone", split)
+		}
+	}
+
+	return fieldGroups, nil
+}
+
+// Same as `VxRule.InGroups`.
+func (g FieldGroup) InGroups(active []string) bool {
+	return VxRule{Groups: g.Groups}.InGroups(active)
+}
+
+// Checks the constraint against the fields that `g.Refs` point to, in the
+// same order. A field counts as present the same way it does for the
+// "requiredWith" rule.
+func (g FieldGroup) Exec(fields []VxField) error {
+	names := []string{}
+	present := 0
+
+	for _, field := range fields {
+		names = append(names, field.DisplayName())
+
+		if !isMissing(field.Value, false) {
+			present++
+		}
+	}
+
+	switch {
+	case g.Key == "exclusive" && present > 1:
+		return fmt.Errorf("only one of %s can be present", strings.Join(names, ", "))
+	case g.Key == "atLeastOne" && present == 0:
+		return fmt.Errorf("at least one of %s is required", strings.Join(names, ", "))
+	case g.Key == "exactlyOne" && present != 1:
+		return fmt.Errorf("exactly one of %s is required", strings.Join(names, ", "))
+	case g.Key == "allOrNone" && present != 0 && present != len(fields):
+		return fmt.Errorf("either all or none of %s must be present", strings.Join(names, ", "))
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -123,25 +124,140 @@ func makeType(s string) (reflect.Type, error) {
 type VxTag struct {
 	Type            reflect.Type
 	HasExplicitType bool
-	Rules           []rule
+	Rules           []VxRule
+	// Value of "default=" in the tag, parsed with `MakeDefault`.
+	Default    string
+	HasDefault bool
+	// Stop executing the rules after the first one that fails, from "bail".
+	// Rules are executed in the order they are in the tag.
+	Bail bool
+	// The name of the field for the messages, from "label=". The name is
+	// used when it's empty.
+	Label string
+	// Message templates from "msg=", by the code of the rule they are for
+	// like "minLength" for "msg(minLength)=". The one for all of the rules of
+	// the field is under "".
+	Messages map[string]string
+}
+
+// A rule in the tag along with the validation groups it belongs to.
+type VxRule struct {
+	// Key of the rule in the tag, like "minLength".
+	Code string
+	Rule rule
+	// Validation groups of the rule, like "create" for "required@create". A
+	// rule without any groups is always executed.
+	Groups []string
+}
+
+// Whether the rule should be executed when the `active` validation groups
+// are selected.
+func (r VxRule) InGroups(active []string) bool {
+	if len(r.Groups) == 0 {
+		return true
+	}
+
+	for _, group := range r.Groups {
+		for _, a := range active {
+			if group == a {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Splits an item of the tag like " minLength@create|update=3" into its key
+// "minLength", value "3" and validation groups "create" and "update". Options
+// in parentheses, like "required(nonZero)", are also given as the value.
+// Items without either, like "required", have an empty value.
+func splitTagItem(s string) (key, value string, groups []string) {
+	key, value, _ = strings.Cut(strings.TrimSpace(s), "=")
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+
+	if k, g, found := strings.Cut(key, "@"); found {
+		key = k
+		groups = strings.Split(g, "|")
+	}
+
+	if left := strings.Index(key, "("); left != -1 && strings.HasSuffix(key, ")") && value == "" {
+		return key[:left], key[left+1 : len(key)-1], groups
+	}
+
+	return key, value, groups
+}
+
+// Splits the tag into its items at the commas. A comma can be in an item as
+// `\,`, like in a message for "msg=". A value in brackets or braces is kept
+// as a single item as well, like "default=[1, 2]" or "default={"a": 1}".
+func splitTag(tagString string) []string {
+	splits := []string{}
+	var sb strings.Builder
+
+	// How deep in the brackets and braces of a value we are.
+	depth := 0
+
+	for i := 0; i < len(tagString); i++ {
+		c := tagString[i]
+
+		switch {
+		case c == '\\' && i+1 < len(tagString) && tagString[i+1] == ',':
+			sb.WriteByte(',')
+			i++
+			continue
+		case c == ',' && depth == 0:
+			splits = append(splits, sb.String())
+			sb.Reset()
+			continue
+		case (c == '[' || c == '{') && (depth > 0 || startsValue(sb.String())):
+			depth++
+		case (c == ']' || c == '}') && depth > 0:
+			depth--
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return append(splits, sb.String())
+}
+
+// Whether the next character after `item` is the first one of its value,
+// i.e. `item` is like "default=".
+func startsValue(item string) bool {
+	return strings.HasSuffix(strings.TrimSpace(item), "=")
+}
+
+// Gives the code of the rule that a "msg" key is for, like "minLength" for
+// "msg(minLength)", and "" for a plain "msg" which is for all the rules.
+func messageCode(key string) (code string, isMsg bool) {
+	if key == "msg" {
+		return "", true
+	}
+
+	if strings.HasPrefix(key, "msg(") && strings.HasSuffix(key, ")") {
+		return key[len("msg(") : len(key)-1], true
+	}
+
+	return "", false
 }
 
 func MakeTag(field VxField) (VxTag, error) {
 	tag := VxTag{
 		Type:            reflect.TypeOf(nil),
 		HasExplicitType: false,
-		Rules:           []rule{},
+		Rules:           []VxRule{},
+		Messages:        map[string]string{},
 	}
 
-	splits := strings.Split(field.TagString, ",")
+	splits := splitTag(field.TagString)
 
 	// Looping first time to just get the "type".
 	// PERFORMANCE: technicallly the time complexity remains O(n) even if we
 	// loop twice over `splits` but maybe somehow not loop twice and get it done?
 	for _, split := range splits {
-		if strings.Contains(split, "type=") {
-			typeStr := strings.Split(split, "=")[1]
-
+		if key, typeStr, _ := splitTagItem(split); key == "type" {
 			tagType, err := makeType(typeStr)
 			if err != nil {
 				err := fmt.Errorf("%s: %s", field.Name, err.Error())
@@ -155,6 +271,13 @@ func MakeTag(field VxField) (VxTag, error) {
 
 	if !tag.HasExplicitType {
 		tag.Type = field.Type
+
+		// The value in a document is always checked against the type the
+		// field has in the struct, as if it was in the tag.
+		if field.SchemaType != nil {
+			tag.Type = field.SchemaType
+			tag.HasExplicitType = true
+		}
 	}
 
 	if tag.Type != field.Type && field.Type.Kind() != reflect.Interface {
@@ -164,11 +287,49 @@ func MakeTag(field VxField) (VxTag, error) {
 
 	// Looping second time to build rules.
 	for _, split := range splits {
-		if strings.Contains(split, "type=") || strings.Contains(split, "name=") {
+		key, v, groups := splitTagItem(split)
+		code, isMsg := messageCode(key)
+
+		if len(groups) > 0 && (key == "type" || key == "name" || key == "label" || key == "default" || key == "bail" || isMsg) {
+			return tag, fmt.Errorf("%s: %s can't be in a validation group, only rules can", field.Name, key)
+		}
+
+		for _, group := range groups {
+			if group == "" {
+				return tag, fmt.Errorf("%s: got an empty validation group in `%s`", field.Name, strings.TrimSpace(split))
+			}
+		}
+
+		if isMsg {
+			if v == "" {
+				return tag, fmt.Errorf("%s: %s should have a message", field.Name, key)
+			}
+
+			tag.Messages[code] = v
+			continue
+		}
+
+		switch key {
+		case "type", "name":
 			// We have already handled these.
-		} else if strings.Contains(split, "minLength=") {
-			v := strings.Split(split, "=")[1]
+		case "bail":
+			tag.Bail = true
+		case "label":
+			if v == "" {
+				return tag, fmt.Errorf("%s: label should have a value", field.Name)
+			}
 
+			tag.Label = v
+		case "default":
+			tag.Default = v
+			tag.HasDefault = true
+
+			// Only to report an invalid default right away, the value is
+			// made again every time it's needed so that it's never shared.
+			if _, err := tag.MakeDefault(); err != nil {
+				return tag, fmt.Errorf("%s: %s", field.Name, err.Error())
+			}
+		case "minLength":
 			i, err := strconv.Atoi(v)
 			if err != nil {
 				return tag, fmt.Errorf("minLength: should be an integer, got %s", v)
@@ -179,11 +340,42 @@ func MakeTag(field VxField) (VxTag, error) {
 			}
 
 			rule := makeMinLength(i)
-			tag.Rules = append(tag.Rules, rule)
-		} else if strings.Contains(split, "required") {
-			rule := makeRequired()
-			tag.Rules = append(tag.Rules, rule)
-		} else {
+			tag.Rules = append(tag.Rules, VxRule{key, rule, groups})
+		case "required":
+			if v != "" && v != "allowZero" && v != "nonZero" {
+				return tag, fmt.Errorf("required: got an invalid option %s, expected allowZero or nonZero", v)
+			}
+
+			rule := makeRequired(v == "nonZero")
+			tag.Rules = append(tag.Rules, VxRule{key, rule, groups})
+		case "notBlank":
+			rule := makeNotBlank()
+			tag.Rules = append(tag.Rules, VxRule{key, rule, groups})
+		case "requiredIf", "requiredUnless", "requiredWith", "requiredWithout":
+			rule, err := makeRequiredIf(key, v)
+			if err != nil {
+				return tag, err
+			}
+
+			tag.Rules = append(tag.Rules, VxRule{key, rule, groups})
+		case "eqField", "neField", "gtField", "gteField", "ltField", "lteField":
+			rule, err := makeCompareField(key, v)
+			if err != nil {
+				return tag, err
+			}
+
+			tag.Rules = append(tag.Rules, VxRule{key, rule, groups})
+		case "present":
+			rule := makePresent()
+			tag.Rules = append(tag.Rules, VxRule{key, rule, groups})
+		case "notNull":
+			if field.IsOptionalPtr {
+				return tag, fmt.Errorf("%s: notNull can't be used on a pointer to Optional, encoding/json makes it nil for null so it's the same as absent, use an Optional without the pointer", field.Name)
+			}
+
+			rule := makeNotNull()
+			tag.Rules = append(tag.Rules, VxRule{key, rule, groups})
+		default:
 			if split != "" {
 				log.Printf("[Vx]: got an invalid value `%s` in the tag", split)
 			}
@@ -193,6 +385,78 @@ func MakeTag(field VxField) (VxTag, error) {
 	return tag, nil
 }
 
+// Makes a new value of `tag.Type` from the "default=" in the tag.
+//
+// Ex:
+// "default=42" with "type=int" -> 42
+// "default=[]" with "type=[]string" -> []string{}
+// "default={}" with "type=map[string]any" -> map[string]any{}
+// "default=[1]" with "type=[]int" -> []int{1}
+// "default=[1, 2]" with "type=[]int" -> []int{1, 2}
+//
+// Anything other than "[]" and "{}" for slices, arrays, maps and structs is
+// parsed as JSON. Without an explicit type on an `any` field, the default is
+// the string as it is.
+func (tag VxTag) MakeDefault() (any, error) {
+	v, err := makeValue(tag.Default, tag.Type)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default '%s' for type %s. %s", tag.Default, tag.Type, err.Error())
+	}
+
+	return v.Interface(), nil
+}
+
+func makeValue(s string, t reflect.Type) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.Interface, reflect.String:
+		return reflect.ValueOf(s).Convert(t), nil
+	case reflect.Pointer:
+		elem, err := makeValue(s, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+
+		return ptr, nil
+	case reflect.Slice:
+		if s == "[]" {
+			return reflect.MakeSlice(t, 0, 0), nil
+		}
+	case reflect.Map:
+		if s == "{}" {
+			return reflect.MakeMap(t), nil
+		}
+	case reflect.Array:
+		if s == "[]" {
+			return reflect.New(t).Elem(), nil
+		}
+	case reflect.Struct:
+		if s == "{}" {
+			return reflect.New(t).Elem(), nil
+		}
+	default:
+		v, ok, err := CoerceString("default", s, t, GoTypeName)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("type %s can't have a default", t)
+		}
+
+		return reflect.ValueOf(v), nil
+	}
+
+	v := reflect.New(t)
+	if err := json.Unmarshal([]byte(s), v.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return v.Elem(), nil
+}
+
 type VxField struct {
 	// Name of the field.
 	Name string
@@ -209,6 +473,104 @@ type VxField struct {
 	//   type of the value that is actually passed to this field.
 	// - If the struct was created during runtime via something like json.Encode, etc.
 	ValueType reflect.Type
+	// The field itself in the parsed struct. It can only be set when a pointer
+	// to the struct was given to `ParseStruct`.
+	Target reflect.Value
+	// Whether the field is an `Optional`. `Type`, `Value` and `ValueType` are
+	// then of the value inside of it.
+	IsOptional bool
+	// Whether the field is a pointer to an `Optional`. encoding/json sets it
+	// to nil for `null` without calling its `UnmarshalJSON`, so it can't tell
+	// `null` from absent.
+	IsOptionalPtr bool
+	// Whether the field was present in the input at all. Only an `Optional`
+	// can really tell, for everything else it's the same as not being nil.
+	Present bool
+	// Whether the field was explicitly `null` in the input. Only an `Optional`
+	// can tell, it's always false for everything else.
+	Null bool
+	// Type the value should have in a document validated without its struct,
+	// see `DocSchema`. `Type` is `any` then and this is used as the type in
+	// the tag when it doesn't have one. Nil for the fields of a struct.
+	SchemaType reflect.Type
+	// Where the field is from the root, see `Path`.
+	Path Path
+	// The name of the field for the messages, set while validating. See
+	// `DisplayName`.
+	Label string
+}
+
+// Gives the name of the field for the messages of the rules, its `Label` or
+// its `Name` when it has none. The params of the errors keep the `Name`.
+func (f VxField) DisplayName() string {
+	if f.Label != "" {
+		return f.Label
+	}
+
+	return f.Name
+}
+
+// Implemented by `vx.Optional` so that `ParseStruct` can tell apart a field
+// that was absent from the input from one that was `null` in it.
+type Optional interface {
+	IsPresent() bool
+	IsNull() bool
+}
+
+// Whether `t` is an `Optional`, or a pointer to one.
+func isOptional(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && t.Implements(optionalType)
+}
+
+// Gives the type of the value inside an `Optional`, or a pointer to one.
+func optionalValueType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	inner, _ := t.FieldByName("Value")
+	return inner.Type
+}
+
+// Assigns `v` to the field in the struct. An `Optional` becomes present and
+// not null. Returns false if the field can't be set, i.e. the struct was not
+// given as a pointer to `ParseStruct`.
+func (f VxField) Set(v any) bool {
+	if !f.Target.CanSet() {
+		return false
+	}
+
+	if f.IsOptional {
+		optional := f.Target
+
+		if optional.Kind() == reflect.Ptr {
+			if optional.IsNil() {
+				optional.Set(reflect.New(optional.Type().Elem()))
+			}
+		} else {
+			optional = optional.Addr()
+		}
+
+		optional.MethodByName("Set").Call([]reflect.Value{reflect.ValueOf(v)})
+		return true
+	}
+
+	f.Target.Set(reflect.ValueOf(v))
+	return true
+}
+
+// Whether `v` is a nil pointer, map, slice or interface.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return false
 }
 
 type VxStruct struct {
@@ -216,6 +578,25 @@ type VxStruct struct {
 	Name string
 	// Fields in the struct.
 	Fields []VxField
+	// The struct itself followed by all the structs nested in it. Their
+	// fields are in `Fields` already, these are for the struct as a whole.
+	Structs []VxStructInfo
+}
+
+type VxStructInfo struct {
+	// Name of the field holding the struct, prefixed the same way as the
+	// names of the fields. Empty for the struct given to `ParseStruct`.
+	Name string
+	// Type of the struct.
+	Type reflect.Type
+	// The struct itself. It's addressable only when a pointer to the struct
+	// was given to `ParseStruct`.
+	Value reflect.Value
+	// The "vx" tags on the blank `_` fields of the struct. They have rules
+	// for the struct as a whole, like `vx:"exclusive=assoc bonus"`.
+	TagStrings []string
+	// Where the struct is from the root, empty for the root itself.
+	Path Path
 }
 
 func ParseStruct(toParse interface{}) (VxStruct, error) {
@@ -233,37 +614,120 @@ func ParseStruct(toParse interface{}) (VxStruct, error) {
 		return VxStruct{}, errors.New(msg)
 	}
 
+	return parseStruct(val)
+}
+
+// Makes a `VxStruct` with a single field holding `v`, as if it was in a
+// struct with the "vx" tag `tagString`. The field is named after "name=" in
+// the tag, "value" otherwise.
+//
+// The field is of type `any`, so "type=" in the tag decides what `v` should
+// be. There is no struct, so `Structs` is empty.
+func ParseValue(v any, tagString string) VxStruct {
+	// Like a field of a struct that wasn't given as a pointer, there is
+	// nothing to set.
+	Target := reflect.ValueOf(struct{ Value any }{v}).Field(0)
+
+	return VxStruct{
+		Name: "",
+		Fields: []VxField{{
+			Name:      tagName(tagString, "value"),
+			Path:      Path{}.Key(tagName(tagString, "value")),
+			Type:      Target.Type(),
+			TagString: tagString,
+			Value:     v,
+			ValueType: reflect.TypeOf(v),
+			Target:    Target,
+			Present:   v != nil,
+		}},
+		Structs: []VxStructInfo{},
+	}
+}
+
+// Gives the "name=" in the tag, or `fallback` if there is none.
+func tagName(tagString string, fallback string) string {
+	splits := splitTag(tagString)
+	for _, split := range splits {
+		if key, v, _ := splitTagItem(split); key == "name" {
+			if len(v) > 0 {
+				// We have a `name` property on the tag, so lets use it.
+				return v
+			}
+		}
+	}
+
+	return fallback
+}
+
+// Does the actual parsing for `ParseStruct`. Works on the `reflect.Value` of
+// the struct instead of a copy of it so that the `Target` of the fields stays
+// settable for nested structs as well.
+func parseStruct(val reflect.Value) (VxStruct, error) {
 	valType := val.Type()
 	fields := []VxField{}
+	info := VxStructInfo{Type: valType, Value: val, TagStrings: []string{}, Path: Path{}}
+	structs := []VxStructInfo{}
 
 	for i := 0; i < valType.NumField(); i++ {
 		field := valType.Field(i)
 
-		Name := field.Name
+		if field.Name == "_" {
+			if tagString, ok := field.Tag.Lookup(VX_TAG_KEY); ok {
+				info.TagStrings = append(info.TagStrings, tagString)
+			}
+
+			continue
+		}
+
 		Type := field.Type
 		TagString := field.Tag.Get(VX_TAG_KEY)
+		Name := tagName(TagString, field.Name)
+		Path := Path{}.Key(pathKey(field, TagString))
+
+		// Like encoding/json, the fields of an embedded struct are in the
+		// same object as the ones of its parent.
+		nestedPath := Path
+		if _, hasKey, _ := jsonKey(field); field.Anonymous && !hasKey {
+			nestedPath = Path[:0]
+		}
 
-		splits := strings.Split(TagString, ",")
-		for _, split := range splits {
-			if strings.Contains(split, "name=") {
-				v := strings.Split(split, "=")[1]
+		Target := val.Field(i)
+		Value := Target.Interface()
+		ValueType := reflect.TypeOf(Value)
+		Present := !isNil(Target)
+		Null := false
 
-				if len(v) > 0 {
-					// We have a `name` property on the tag, so lets use it.
-					Name = v
-				}
+		opt, IsOptional := Value.(Optional)
+
+		if IsOptional {
+			// Looking inside the `Optional`, validating it is all about the
+			// value it holds. A nil pointer to one is an absent one.
+			optional := Target
+			if optional.Kind() == reflect.Ptr && !optional.IsNil() {
+				optional = optional.Elem()
 			}
-		}
 
-		Value := reflect.Indirect(reflect.ValueOf(toParse)).FieldByName(field.Name).Interface()
-		ValueType := reflect.TypeOf(Value)
+			Type = optionalValueType(Target.Type())
+			Present = false
+			Null = false
+			Value = nil
+
+			if optional.Kind() == reflect.Struct {
+				Present = opt.IsPresent()
+				Null = opt.IsNull()
 
-		// Checking if we have a Type on a Field which is another custom `type`(Go keyword).
-		if strings.Contains(Type.String(), ".") && strings.Contains(ValueType.String(), ".") {
+				if Present && !Null {
+					Value = optional.FieldByName("Value").Interface()
+				}
+			}
+
+			ValueType = reflect.TypeOf(Value)
+		} else if strings.Contains(Type.String(), ".") && strings.Contains(ValueType.String(), ".") {
+			// Checking if we have a Type on a Field which is another custom `type`(Go keyword).
 			// Switching over the actual `type`.
 			switch Type.Kind() {
 			case reflect.Struct:
-				parsedStruct, err := ParseStruct(Value)
+				parsedStruct, err := parseStruct(Target)
 				if err != nil {
 					return VxStruct{}, err
 				}
@@ -273,19 +737,39 @@ func ParseStruct(toParse interface{}) (VxStruct, error) {
 					for i, field := range parsedStruct.Fields {
 						// Prefixing the Field's name with it's parent field's name.
 						parsedStruct.Fields[i].Name = Name + "." + field.Name
+						parsedStruct.Fields[i].Path = nestedPath.Join(field.Path)
+					}
+
+					for i, nested := range parsedStruct.Structs {
+						parsedStruct.Structs[i].Name = strings.TrimSuffix(Name+"."+nested.Name, ".")
+						parsedStruct.Structs[i].Path = nestedPath.Join(nested.Path)
 					}
 				}
 
 				fields = append(fields, parsedStruct.Fields...)
+				structs = append(structs, parsedStruct.Structs...)
 			}
 		}
 
-		fields = append(fields, VxField{Name, Type, TagString, Value, ValueType})
+		fields = append(fields, VxField{
+			Name:          Name,
+			Type:          Type,
+			TagString:     TagString,
+			Value:         Value,
+			ValueType:     ValueType,
+			Target:        Target,
+			IsOptional:    IsOptional,
+			IsOptionalPtr: IsOptional && Target.Kind() == reflect.Ptr,
+			Present:       Present,
+			Null:          Null,
+			Path:          Path,
+		})
 	}
 
 	return VxStruct{
-		Name:   val.Type().Name(),
-		Fields: fields,
+		Name:    val.Type().Name(),
+		Fields:  fields,
+		Structs: append([]VxStructInfo{info}, structs...),
 	}, nil
 }
 
@@ -298,15 +782,84 @@ type rule interface {
 // Rules allowed for all types.
 //
 
-type required struct{}
+// What counts as missing depends on the kind of the value:
+// - nil, which includes nil pointers, slices and maps and an absent or null `Optional`.
+// - string: empty or only whitespace.
+// - bool: never, false is a value, unless it's "required(nonZero)".
+// - numbers: never, 0 is a value, unless it's "required(nonZero)".
+// - slice and map: no elems.
+// - array and struct: all elems or fields are zero values.
+//
+// A non nil pointer is never missing, whatever it points to. The default can
+// also be spelled out as "required(allowZero)".
+type required struct {
+	nonZero bool
+}
 
-func makeRequired() required {
-	return required{}
+func makeRequired(nonZero bool) required {
+	return required{nonZero}
 }
 
 func (r required) Exec(field VxField) error {
-	if field.Value == nil || field.Value == "" {
-		return fmt.Errorf("%s is required", field.Name)
+	if isMissing(field.Value, r.nonZero) {
+		return fmt.Errorf("%s is required", field.DisplayName())
+	}
+
+	return nil
+}
+
+// Whether `v` counts as missing for the "required" rule, 0 and false too
+// when `nonZero`.
+func isMissing(v any, nonZero bool) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(rv.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	case reflect.Array, reflect.Struct:
+		return rv.IsZero()
+	case reflect.Bool:
+		return nonZero && !rv.Bool()
+	}
+
+	if hasNumbers(rv.Type()) {
+		return nonZero && rv.IsZero()
+	}
+
+	return false
+}
+
+type present struct{}
+
+func makePresent() present {
+	return present{}
+}
+
+func (r present) Exec(field VxField) error {
+	if !field.Present {
+		return fmt.Errorf("%s must be present", field.DisplayName())
+	}
+
+	return nil
+}
+
+type notNull struct{}
+
+func makeNotNull() notNull {
+	return notNull{}
+}
+
+func (r notNull) Exec(field VxField) error {
+	if field.Null {
+		return fmt.Errorf("%s must not be null", field.DisplayName())
 	}
 
 	return nil
@@ -316,6 +869,33 @@ func (r required) Exec(field VxField) error {
 // Rules allowed only for string.
 //
 
+// Fails for strings that are empty or only have whitespace. Unlike "required"
+// a nil value is fine, so it's for fields that can be left out but must have
+// something in them when they are not.
+type notBlank struct{}
+
+func makeNotBlank() notBlank {
+	return notBlank{}
+}
+
+func (r notBlank) Exec(field VxField) error {
+	if field.Value == nil {
+		return nil
+	}
+
+	s, ok := field.Value.(string)
+	if !ok {
+		err := fmt.Errorf("%s - notBlank: rule can only be applied to type string but was applied to type %s", field.DisplayName(), field.ValueType)
+		return WithParams(err, map[string]any{"expected": "string", "actual": fmt.Sprint(field.ValueType)})
+	}
+
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("%s must not be blank", field.DisplayName())
+	}
+
+	return nil
+}
+
 type minLength struct {
 	value int
 }
@@ -329,7 +909,10 @@ func (r minLength) Exec(field VxField) error {
 		return nil
 	}
 
-	wrongTypeErr := fmt.Errorf("%s - minLength: rule can only be applied to type string but was applied to type %s", field.Name, field.ValueType)
+	wrongTypeErr := WithParams(
+		fmt.Errorf("%s - minLength: rule can only be applied to type string but was applied to type %s", field.DisplayName(), field.ValueType),
+		map[string]any{"expected": "string", "actual": fmt.Sprint(field.ValueType)},
+	)
 
 	if field.Type.Kind() != reflect.String && field.Type.Kind() != reflect.Interface {
 		return wrongTypeErr
@@ -341,7 +924,8 @@ func (r minLength) Exec(field VxField) error {
 	}
 
 	if len(s) < r.value {
-		return fmt.Errorf("%s should have a minimum length of %v but has %v", field.Name, r.value, len(s))
+		err := fmt.Errorf("%s should have a minimum length of %v but has %v", field.DisplayName(), r.value, len(s))
+		return WithParams(err, map[string]any{"limit": r.value, "actual": len(s)})
 	}
 
 	return nil
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A step in a `Path`, either a key of an object or an index of an array.
+type PathSegment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// Where a field is from the root of what was validated, one segment for each
+// nested object and array. Keys are the "name=" in the tag of a field, else
+// its name in the "json" tag, else its name in the struct. Unlike the names of
+// the fields, nothing in a key has to be escaped.
+type Path []PathSegment
+
+// Gives a new path with the `key` appended to `p`.
+func (p Path) Key(key string) Path {
+	return append(append(Path{}, p...), PathSegment{Key: key})
+}
+
+// Gives a new path with the index `i` appended to `p`.
+func (p Path) Index(i int) Path {
+	return append(append(Path{}, p...), PathSegment{Index: i, IsIndex: true})
+}
+
+// Gives a new path with `other` appended to `p`.
+func (p Path) Join(other Path) Path {
+	return append(append(Path{}, p...), other...)
+}
+
+// Renders the path as an RFC 6901 JSON Pointer, like "/complexA/simple_a/3".
+// "~" and "/" in the keys are escaped as "~0" and "~1". The empty path, the
+// root, is "".
+func (p Path) JSONPointer() string {
+	var sb strings.Builder
+
+	for _, seg := range p {
+		sb.WriteString("/")
+
+		if seg.IsIndex {
+			sb.WriteString(strconv.Itoa(seg.Index))
+			continue
+		}
+
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(seg.Key))
+	}
+
+	return sb.String()
+}
+
+// Renders the path in the dotted-bracket form, like "complexA.simple_a[3]".
+// Keys which can't be written as they are, like the ones with a "." in them,
+// are quoted in brackets, like `filter["a.b"]`.
+func (p Path) String() string {
+	var sb strings.Builder
+
+	for i, seg := range p {
+		switch {
+		case seg.IsIndex:
+			sb.WriteString("[" + strconv.Itoa(seg.Index) + "]")
+		case seg.Key == "" || strings.ContainsAny(seg.Key, `.[]"`):
+			sb.WriteString("[" + strconv.Quote(seg.Key) + "]")
+		default:
+			if i > 0 {
+				sb.WriteString(".")
+			}
+
+			sb.WriteString(seg.Key)
+		}
+	}
+
+	return sb.String()
+}
+
+// A path is an array in JSON, with a string for a key and a number for an
+// index, like `["items", 1, "sku"]`. The root is `[]`.
+func (p Path) MarshalJSON() ([]byte, error) {
+	segs := []any{}
+
+	for _, seg := range p {
+		if seg.IsIndex {
+			segs = append(segs, seg.Index)
+			continue
+		}
+
+		segs = append(segs, seg.Key)
+	}
+
+	return json.Marshal(segs)
+}
+
+func (p *Path) UnmarshalJSON(data []byte) error {
+	var segs []any
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(&segs); err != nil {
+		return err
+	}
+
+	*p = Path{}
+
+	for _, seg := range segs {
+		switch s := seg.(type) {
+		case string:
+			*p = p.Key(s)
+		case json.Number:
+			i, err := s.Int64()
+			if err != nil {
+				return fmt.Errorf("path: an index should be an integer, got %s", s)
+			}
+
+			*p = p.Index(int(i))
+		default:
+			return fmt.Errorf("path: expected a string or an integer, got %v", seg)
+		}
+	}
+
+	return nil
+}
+
+// Makes a path out of a name of a field like "address.lines[0]", for the
+// names which don't come with a path, like the ones given by a `Validator`.
+func ParsePath(name string) Path {
+	p := Path{}
+
+	for _, part := range strings.Split(name, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			p = p.Key(key)
+		}
+
+		for rest != "" {
+			index, after, _ := strings.Cut(rest, "]")
+
+			if i, err := strconv.Atoi(index); err == nil {
+				p = p.Index(i)
+			} else {
+				p = p.Key(index)
+			}
+
+			_, rest, _ = strings.Cut(after, "[")
+		}
+	}
+
+	return p
+}
+
+// Gives the key of the field in a `Path`: the "name=" in the tag, else the
+// name in the "json" tag, else the name of the field in the struct.
+func pathKey(field reflect.StructField, tagString string) string {
+	if key, hasKey, _ := jsonKey(field); hasKey {
+		return tagName(tagString, key)
+	}
+
+	return tagName(tagString, field.Name)
+}
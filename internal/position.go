@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Where the values are in a JSON source.
+type Positions struct {
+	// By the full names of the fields, like the ones in `VxField.Name`. The
+	// root object is "".
+	byName map[string]Position
+	// By the offset right after the value, which is where encoding/json
+	// says it found a value of the wrong type.
+	byEnd map[int]Position
+}
+
+// Gives the position of the field with the full `name`. A field that isn't in
+// the source gives the position of the closest object it would be in, e.g.
+// "filter" for "filter.kind", so there always is something to point at.
+func (p Positions) Find(name string) (Position, bool) {
+	for {
+		if pos, ok := p.byName[name]; ok {
+			return pos, true
+		}
+
+		if name == "" {
+			return Position{}, false
+		}
+
+		i := strings.LastIndexAny(name, ".[")
+		if i == -1 {
+			i = 0
+		}
+
+		name = name[:i]
+	}
+}
+
+// Gives the position of the value which ends at `offset`, or of the `offset`
+// itself if no value ends there.
+func (p Positions) FindEnd(data []byte, offset int) Position {
+	if pos, ok := p.byEnd[offset]; ok {
+		return pos
+	}
+
+	return MakePosition(data, offset)
+}
+
+// Makes the `Position` of the `offset` in `data`.
+func MakePosition(data []byte, offset int) Position {
+	if offset > len(data) {
+		offset = len(data)
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	before := data[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+
+	return Position{
+		Offset: offset,
+		Line:   bytes.Count(before, []byte("\n")) + 1,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+	}
+}
+
+// Finds where the values of the fields are in the JSON object `data`, named
+// the same way as the fields in `Parse`. The keys which aren't fields of the
+// struct are named like in `UnknownKeys` and point at the key instead of the
+// value, as it's the key that is wrong.
+//
+// Gives the positions found before the JSON turns out to be invalid, if it is.
+func (s *DocSchema) Positions(data []byte) (Positions, error) {
+	w := positionWalker{
+		data: data,
+		d:    json.NewDecoder(bytes.NewReader(data)),
+		positions: Positions{
+			byName: map[string]Position{},
+			byEnd:  map[int]Position{},
+		},
+	}
+
+	err := w.walk("", s, false)
+
+	return w.positions, err
+}
+
+type positionWalker struct {
+	data      []byte
+	d         *json.Decoder
+	positions Positions
+}
+
+// Gives the offset where the next token starts.
+func (w *positionWalker) next() int {
+	offset := int(w.d.InputOffset())
+
+	for offset < len(w.data) && strings.IndexByte(" \t\r\n,:", w.data[offset]) != -1 {
+		offset++
+	}
+
+	return offset
+}
+
+// Records the position of the value named `name` and everything in it. The
+// value should be an object of the struct `s`, or an array of them if
+// `isList`, when `s` is not nil.
+func (w *positionWalker) walk(name string, s *DocSchema, isList bool) error {
+	pos := MakePosition(w.data, w.next())
+	w.positions.byName[name] = pos
+
+	tok, err := w.d.Token()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		w.positions.byEnd[int(w.d.InputOffset())] = pos
+	}()
+
+	switch tok {
+	case json.Delim('{'):
+		var fields []objectField
+		if s != nil && !isList {
+			fields = s.objectFields(strings.TrimPrefix(name+".", "."), Path{})
+		}
+
+		for w.d.More() {
+			keyOffset := w.next()
+
+			tok, err := w.d.Token()
+			if err != nil {
+				return err
+			}
+
+			key, _ := tok.(string)
+
+			if f, ok := findObjectField(fields, key); ok {
+				if err := w.walk(f.name, f.field.Struct, f.field.IsList); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			keyName := strings.TrimPrefix(name+"."+key, ".")
+			if err := w.walk(keyName, nil, false); err != nil {
+				return err
+			}
+
+			if s != nil && !isList {
+				w.positions.byName[keyName] = MakePosition(w.data, keyOffset)
+			}
+		}
+
+		_, err = w.d.Token()
+		return err
+	case json.Delim('['):
+		var elem *DocSchema
+		if isList {
+			elem = s
+		}
+
+		for i := 0; w.d.More(); i++ {
+			if err := w.walk(fmt.Sprintf("%s[%d]", name, i), elem, false); err != nil {
+				return err
+			}
+		}
+
+		_, err = w.d.Token()
+		return err
+	}
+
+	return nil
+}
+
+// Finds the field for the `key` of an object, ignoring the case if it's not
+// there as it is, like `lookupKey`.
+func findObjectField(fields []objectField, key string) (objectField, bool) {
+	for _, f := range fields {
+		if f.field.Key == key {
+			return f, true
+		}
+	}
+
+	for _, f := range fields {
+		if strings.EqualFold(f.field.Key, key) {
+			return f, true
+		}
+	}
+
+	return objectField{}, false
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Gives the name of a type for the messages.
+type TypeNamer func(t reflect.Type) string
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// Gives the Go name of the type `t`, like "int8" or "[]string".
+func GoTypeName(t reflect.Type) string {
+	return fmt.Sprint(t)
+}
+
+// Gives the JSON Schema name of the type `t`, like "integer" for int8 or
+// "array of string" for []string.
+func JSONTypeName(t reflect.Type) string {
+	if t == nil {
+		return "null"
+	}
+
+	if t == jsonNumberType {
+		return "number"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Interface {
+			return "array"
+		}
+
+		return "array of " + JSONTypeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("%s with %d items", JSONTypeName(reflect.SliceOf(t.Elem())), t.Len())
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Ptr:
+		return JSONTypeName(t.Elem())
+	case reflect.Interface:
+		return "any"
+	default:
+		return t.Kind().String()
+	}
+}
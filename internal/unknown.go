@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A key in a document that isn't a field of the struct it's validated against.
+type UnknownKey struct {
+	// Full name of the key, named like the fields, e.g. "address.zipp".
+	Name string
+	// Where the key is from the root.
+	Path Path
+	// The key of a field in the same object that looks like it, if any.
+	Suggestion string
+}
+
+func (k UnknownKey) Error() string {
+	if k.Suggestion == "" {
+		return fmt.Sprintf("%s is not a known field", k.Name)
+	}
+
+	return fmt.Sprintf("%s is not a known field, did you mean %s?", k.Name, k.Suggestion)
+}
+
+// Finds the keys in the document `m` which aren't fields of the struct, in
+// the nested objects and arrays of them as well. Keys of a map field or in an
+// `any` field are never unknown. Nor are the ones `allowed` says are.
+func (s *DocSchema) UnknownKeys(m map[string]any, allowed func(name string) bool) []UnknownKey {
+	return s.unknownKeys("", Path{}, m, allowed)
+}
+
+func (s *DocSchema) unknownKeys(prefix string, path Path, m map[string]any, allowed func(name string) bool) []UnknownKey {
+	unknown := []UnknownKey{}
+	fields := s.objectFields(prefix, path)
+
+	// The nested objects are checked first, in the order of the fields, and
+	// the keys of `m` itself after that, sorted. So that the keys are reported
+	// in the same order every time.
+	known := map[string]bool{}
+
+	for _, f := range fields {
+		value, found := lookupKey(m, f.field.Key)
+		if !found {
+			continue
+		}
+
+		known[matchedKey(m, f.field.Key)] = true
+
+		if f.field.Struct == nil {
+			continue
+		}
+
+		if !f.field.IsList {
+			if obj, isObj := value.(map[string]any); isObj {
+				unknown = append(unknown, f.field.Struct.unknownKeys(f.name+".", f.path, obj, allowed)...)
+			}
+
+			continue
+		}
+
+		list, _ := value.([]any)
+		for i, elem := range list {
+			if obj, isObj := elem.(map[string]any); isObj {
+				unknown = append(unknown, f.field.Struct.unknownKeys(fmt.Sprintf("%s[%d].", f.name, i), f.path.Index(i), obj, allowed)...)
+			}
+		}
+	}
+
+	keys := sortedKeys(m)
+
+	for _, key := range keys {
+		name := prefix + key
+		if known[key] || allowed(name) {
+			continue
+		}
+
+		candidates := []string{}
+		for _, f := range fields {
+			candidates = append(candidates, f.field.Key)
+		}
+
+		unknown = append(unknown, UnknownKey{Name: name, Path: path.Key(key), Suggestion: closest(key, candidates)})
+	}
+
+	return unknown
+}
+
+// A field in an object along with its full name and path.
+type objectField struct {
+	field DocField
+	name  string
+	path  Path
+}
+
+// Gives the fields that are keys of the same object, which are the fields of
+// the struct and of its embedded structs.
+func (s *DocSchema) objectFields(prefix string, path Path) []objectField {
+	fields := []objectField{}
+
+	for _, f := range s.Fields {
+		if f.Embedded {
+			fields = append(fields, f.Struct.objectFields(prefix+f.Name+".", path)...)
+			continue
+		}
+
+		fields = append(fields, objectField{field: f, name: prefix + f.Name, path: path.Key(f.PathKey)})
+	}
+
+	return fields
+}
+
+// Gives the key in `m` that `lookupKey` finds for `key`.
+func matchedKey(m map[string]any, key string) string {
+	if _, ok := m[key]; ok {
+		return key
+	}
+
+	for _, k := range sortedKeys(m) {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+
+	return key
+}
+
+// Gives the keys of `m` in order.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Gives the candidate closest to `s`, as long as it's close enough to be a
+// typo of it. Empty if none of them are.
+func closest(s string, candidates []string) string {
+	best := ""
+	bestDistance := -1
+
+	for _, c := range candidates {
+		d := levenshtein(strings.ToLower(s), strings.ToLower(c))
+		if bestDistance == -1 || d < bestDistance {
+			best = c
+			bestDistance = d
+		}
+	}
+
+	// A third of the key can be wrong, but at least a couple of letters for
+	// the short ones.
+	if bestDistance == -1 || bestDistance > 2 && bestDistance > len(s)/3 {
+		return ""
+	}
+
+	return best
+}
+
+// The number of letters to add, remove or change to turn `a` into `b`.
+func levenshtein(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minOf(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(y)]
+}
+
+func minOf(n int, rest ...int) int {
+	for _, r := range rest {
+		if r < n {
+			n = r
+		}
+	}
+
+	return n
+}
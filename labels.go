@@ -0,0 +1,80 @@
+package vx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Gives the label of the field at `path` for the messages, `label` being the
+// one from "label=" in its tag or "" when it has none. The name of the field
+// is used when it gives "".
+//
+// Ex:
+//
+//	labels := func(path vx.Path, label string) string {
+//		return translations[locale][path.String()]
+//	}
+//
+//	res, ok := vx.ValidateStruct(u, vx.WithLabels(labels))
+type LabelResolver func(path Path, label string) string
+
+// A `LabelResolver` which makes a label out of the last key of the path for
+// the fields without a "label=", like "Simple A" for "ComplexA.simple_a" or
+// "User ID" for "userID".
+func HumanizeLabel(path Path, label string) string {
+	if label != "" {
+		return label
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if !path[i].IsIndex {
+			return humanize(path[i].Key)
+		}
+	}
+
+	return ""
+}
+
+// Splits `key` into words at "_", "-", spaces and the changes of case, and
+// capitalizes them.
+func humanize(key string) string {
+	words := []string{}
+	word := []rune{}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+
+			word = []rune{}
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := word[len(word)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			// "userID" is "user ID" and "IDCard" is "ID Card".
+			if !unicode.IsUpper(prev) || nextIsLower {
+				words = append(words, string(word))
+				word = []rune{}
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,155 @@
+package vx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLabels(t *testing.T) {
+	type simple struct {
+		SimpleA any `json:"simple_a" vx:"name=simple_a, type=string, minLength=3"`
+	}
+
+	type account struct {
+		ComplexA simple
+		Email    any `json:"email" vx:"name=email, label=E-mail address, type=string, required"`
+		Confirm  any `json:"confirm" vx:"name=confirm, label=Confirmation, eqField=email"`
+		UserID   any `json:"userID" vx:"name=userID, type=int"`
+	}
+
+	value := account{ComplexA: simple{SimpleA: "ab"}, Confirm: "a@b.c", UserID: "1"}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name: "labels from the tag should be used",
+			expected: []string{
+				"userID should be of type int but got string",
+				"ComplexA.simple_a should have a minimum length of 3 but has 2",
+				"E-mail address is required",
+			},
+		},
+		{
+			name: "resolver should give the labels",
+			opts: []Option{WithLabels(HumanizeLabel)},
+			expected: []string{
+				"User ID should be of type int but got string",
+				"Simple A should have a minimum length of 3 but has 2",
+				"E-mail address is required",
+			},
+		},
+		{
+			name: "resolver should get the labels from the tag",
+			opts: []Option{WithLabels(func(path Path, label string) string {
+				if label == "" {
+					return ""
+				}
+
+				return "«" + label + "»"
+			})},
+			expected: []string{
+				"userID should be of type int but got string",
+				"ComplexA.simple_a should have a minimum length of 3 but has 2",
+				"«E-mail address» is required",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, _ := ValidateStruct(value, tc.opts...)
+
+			if len(res.Errors) != len(tc.expected) {
+				t.Fatalf("expected %d errors but got %v", len(tc.expected), res.Errors)
+			}
+
+			for i, err := range res.Errors {
+				if err.Error() != tc.expected[i] {
+					t.Errorf("expected %q but got %q", tc.expected[i], err.Error())
+				}
+			}
+		})
+	}
+
+	t.Run("field and path should stay the same", func(t *testing.T) {
+		res, _ := ValidateStruct(value, WithLabels(HumanizeLabel))
+
+		var fieldErr *FieldError
+		if len(res.Errors) != 3 || !errors.As(res.Errors[1], &fieldErr) {
+			t.Fatalf("expected a field error but got %v", res.Errors)
+		}
+
+		if fieldErr.Field != "ComplexA.simple_a" || fieldErr.Path.String() != "ComplexA.simple_a" {
+			t.Errorf("expected ComplexA.simple_a but got %s at %s", fieldErr.Field, fieldErr.Path)
+		}
+	})
+
+	t.Run("cross field rules should use the label of the other field", func(t *testing.T) {
+		res, _ := ValidateStruct(account{ComplexA: simple{SimpleA: "abc"}, Email: "a@b.c", Confirm: "a@b.d", UserID: 1})
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "Confirmation must be equal to E-mail address" {
+			t.Fatalf("expected the labels in the message but got %v", res.Errors)
+		}
+
+		var fieldErr *FieldError
+		if !errors.As(res.Errors[0], &fieldErr) || fieldErr.Field != "confirm" || fieldErr.Params["other"] != "email" {
+			t.Errorf("expected the names in the error but got %+v", fieldErr)
+		}
+	})
+
+	t.Run("templates should have the label of the other field", func(t *testing.T) {
+		RegisterMessage("eqField", "{label} and {other} don't match")
+		t.Cleanup(func() {
+			RegisterMessage("eqField", "")
+		})
+
+		res, _ := ValidateStruct(account{ComplexA: simple{SimpleA: "abc"}, Email: "a@b.c", Confirm: "a@b.d", UserID: 1})
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "Confirmation and E-mail address don't match" {
+			t.Errorf("expected the labels in the message but got %v", res.Errors)
+		}
+	})
+
+	t.Run("templates should have the label", func(t *testing.T) {
+		res, _ := ValidateValue("", "name=email, label=E-mail, required, msg={label} ({field}) is missing")
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "E-mail (email) is missing" {
+			t.Errorf("expected the label in the message but got %v", res.Errors)
+		}
+	})
+
+	t.Run("empty and grouped labels should be schema errors", func(t *testing.T) {
+		for _, tag := range []string{"label=, required", "label@create=Email, required"} {
+			if _, ok := ValidateValue("", tag); ok {
+				t.Errorf("expected %q to be an internal error", tag)
+			}
+		}
+	})
+}
+
+func TestHumanizeLabel(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"simple_a", "Simple A"},
+		{"complexA", "Complex A"},
+		{"userID", "User ID"},
+		{"IDCard", "ID Card"},
+		{"first-name", "First Name"},
+		{"Email", "Email"},
+	}
+
+	for _, tc := range tests {
+		if got := HumanizeLabel(Path{}.Key("user").Key(tc.key), ""); got != tc.expected {
+			t.Errorf("%s: expected %q but got %q", tc.key, tc.expected, got)
+		}
+	}
+
+	if got := HumanizeLabel(Path{}.Key("items").Index(2), ""); got != "Items" {
+		t.Errorf("expected the last key to be used but got %q", got)
+	}
+}
@@ -0,0 +1,139 @@
+package vx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var messages = struct {
+	sync.RWMutex
+	templates map[string]string
+}{templates: map[string]string{}}
+
+// Registers the message template for all the errors with the `code`, like
+// "minLength" or "type". A template given in the tag of the field with "msg="
+// takes precedence, then the one in the `Catalog` of the locale, and the
+// default message is used when there is none.
+//
+// The placeholders are replaced with:
+//   - {field}: the name of the field
+//   - {label}: the label of the field, see `WithLabels`
+//   - {value}: the value of the field
+//   - {code}: the code of the error
+//   - the params of the error, like {limit} and {actual} for "minLength",
+//     {expected} and {actual} for "type", or {other} for "eqField" which is
+//     the label of the other field
+//
+// The ones which the error doesn't have are left as they are.
+//
+// Ex:
+//
+//	vx.RegisterMessage("minLength", "{label} needs at least {limit} characters")
+//
+// A message for a single field goes in the tag, `\,` being a comma in it.
+// "msg=" is for all the rules of the field and "msg(code)=" for just one.
+//
+//	Name any `vx:"type=string, minLength=3, msg(minLength)=too short\, try again"`
+func RegisterMessage(code string, template string) {
+	messages.Lock()
+	defer messages.Unlock()
+
+	if template == "" {
+		delete(messages.templates, code)
+		return
+	}
+
+	messages.templates[code] = template
+}
+
+func registeredMessage(code string) (string, bool) {
+	messages.RLock()
+	defer messages.RUnlock()
+
+	template, ok := messages.templates[code]
+	return template, ok
+}
+
+// An error with its message given by a template. The original error is still
+// there for `errors.Is` and `errors.As`.
+type messageError struct {
+	msg string
+	err error
+}
+
+func (e *messageError) Error() string {
+	return e.msg
+}
+
+func (e *messageError) Unwrap() error {
+	return e.err
+}
+
+// Replaces the message of `fieldErr` with the template for it, if any. The
+// ones from the tag, in `tagMessages`, come first, then the ones from the
+// catalogs of the locales in `o` and then the registered ones. `label` gives
+// the labels of the fields by their names.
+func applyMessage(fieldErr *FieldError, tagMessages map[string]string, value any, label func(string) string, o options) {
+	template, ok := tagMessages[fieldErr.Code]
+	if !ok {
+		template, ok = tagMessages[""]
+	}
+
+	if !ok {
+		template, ok = catalogMessage(o.locales, fieldErr.Code, fieldErr.Params)
+	}
+
+	if !ok {
+		template, ok = registeredMessage(fieldErr.Code)
+	}
+
+	if !ok {
+		return
+	}
+
+	data := map[string]any{
+		"field": fieldErr.Field,
+		"label": label(fieldErr.Field),
+		"value": value,
+		"code":  fieldErr.Code,
+	}
+
+	for key, param := range fieldErr.Params {
+		data[key] = param
+	}
+
+	// The other field is a name in the params, but a label in the message.
+	if other, ok := data["other"].(string); ok {
+		data["other"] = label(other)
+	}
+
+	fieldErr.Err = &messageError{msg: renderMessage(template, data), err: fieldErr.Err}
+}
+
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+func renderMessage(template string, data map[string]any) string {
+	return placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		value, ok := data[match[1:len(match)-1]]
+		if !ok {
+			return match
+		}
+
+		switch value := value.(type) {
+		case nil:
+			return "null"
+		case []string:
+			return strings.Join(value, ", ")
+		default:
+			return fmt.Sprint(value)
+		}
+	})
+}
+
+// Gives `name` as its own label, for the errors which aren't of a field with
+// a tag.
+func sameLabel(name string) string {
+	return name
+}
@@ -0,0 +1,114 @@
+package vx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"vx/internal"
+)
+
+func TestMessages(t *testing.T) {
+	type signup struct {
+		Name     any `json:"name" vx:"name=name, type=string, minLength=3, msg(minLength)=name needs {limit} characters\\, got {actual}"`
+		Nick     any `json:"nick" vx:"name=nick, type=string, minLength=3, msg={field} is not valid"`
+		Password any `json:"password" vx:"name=password, type=string, minLength=8"`
+		Confirm  any `json:"confirm" vx:"name=confirm, eqField=password, msg(eqField)=should match {other}"`
+	}
+
+	t.Run("tag messages should be used", func(t *testing.T) {
+		res, _ := ValidateStruct(signup{Name: "ab", Nick: 1, Password: "password", Confirm: "passw0rd"})
+
+		expected := []string{"nick is not valid", "name needs 3 characters, got 2", "should match password"}
+		if len(res.Errors) != len(expected) {
+			t.Fatalf("expected %d errors but got %v", len(expected), res.Errors)
+		}
+
+		for i, err := range res.Errors {
+			if err.Error() != expected[i] {
+				t.Errorf("expected %q but got %q", expected[i], err.Error())
+			}
+		}
+	})
+
+	t.Run("registered messages should be used after the tag ones", func(t *testing.T) {
+		RegisterMessage("minLength", "{label} needs at least {limit} characters")
+		RegisterMessage("type", "{field} should be {expected}, not {value}")
+		t.Cleanup(func() {
+			RegisterMessage("minLength", "")
+			RegisterMessage("type", "")
+		})
+
+		res, _ := ValidateStruct(signup{Name: "ab", Nick: 1, Password: "pass"})
+
+		expected := []string{"nick is not valid", "name needs 3 characters, got 2", "password needs at least 8 characters"}
+		if len(res.Errors) != len(expected) {
+			t.Fatalf("expected %d errors but got %v", len(expected), res.Errors)
+		}
+
+		for i, err := range res.Errors {
+			if err.Error() != expected[i] {
+				t.Errorf("expected %q but got %q", expected[i], err.Error())
+			}
+		}
+
+		res, _ = ValidateValue(true, "name=age, type=int")
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "age should be int, not true" {
+			t.Errorf("expected the type template but got %v", res.Errors)
+		}
+	})
+
+	t.Run("defaults should be used without a template", func(t *testing.T) {
+		res, _ := ValidateStruct(signup{Name: "abc", Nick: "abc", Password: "pass"})
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "password should have a minimum length of 8 but has 4" {
+			t.Errorf("expected the default message but got %v", res.Errors)
+		}
+	})
+
+	t.Run("errors should still be unwrapped", func(t *testing.T) {
+		RegisterMessage("type", "{field} should be a whole number")
+		t.Cleanup(func() {
+			RegisterMessage("type", "")
+		})
+
+		res, _ := ValidateValue(1.5, "name=count, type=int")
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "count should be a whole number" {
+			t.Fatalf("expected the template but got %v", res.Errors)
+		}
+
+		if !errors.Is(res.Errors[0], internal.ErrNotWholeNumber) {
+			t.Errorf("expected the error to be ErrNotWholeNumber")
+		}
+	})
+
+	t.Run("unknown placeholders should be left as they are", func(t *testing.T) {
+		res, _ := ValidateValue("ab", "name=code, type=string, minLength=3, msg={field} {nope}")
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "code {nope}" {
+			t.Errorf("expected the placeholder to be left but got %v", res.Errors)
+		}
+	})
+
+	t.Run("empty and grouped messages should be schema errors", func(t *testing.T) {
+		for _, tag := range []string{"minLength=3, msg=", "minLength=3, msg@create=nope"} {
+			if _, ok := ValidateValue("ab", tag); ok {
+				t.Errorf("expected %q to be an internal error", tag)
+			}
+		}
+	})
+
+	t.Run("decode errors should use registered messages", func(t *testing.T) {
+		RegisterMessage("unknownField", "{field} isn't allowed, try {suggestion}")
+		t.Cleanup(func() {
+			RegisterMessage("unknownField", "")
+		})
+
+		var s decodeSearch
+		res, _ := Decode(strings.NewReader(`{"querry": "vx"}`), &s)
+
+		if len(res.Errors) == 0 || res.Errors[0].Error() != "querry isn't allowed, try query" {
+			t.Errorf("expected the template but got %v", res.Errors)
+		}
+	})
+}
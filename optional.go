@@ -0,0 +1,86 @@
+package vx
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// A field that remembers whether it was present in the JSON it was decoded
+// from and whether it was `null` there. A plain field can't tell those apart
+// from each other, which matters for something like a PATCH request.
+//
+// Ex:
+//
+//	type userPatch struct {
+//		Name vx.Optional[string] `vx:"name=name, notNull, minLength=3"`
+//	}
+//
+// `{}` -> absent, `{"name": null}` -> null, `{"name": "abc"}` -> "abc".
+//
+// The rules are run on the value inside, an absent or null `Optional` has a
+// nil value for them. Check presence with the "present" and "notNull" rules.
+//
+// Don't use a pointer to an `Optional` when `null` matters. encoding/json sets
+// the pointer to nil for `null` without asking the `Optional`, so a nil one is
+// taken as absent and "notNull" on it is reported as an internal error.
+type Optional[T any] struct {
+	Value   T
+	present bool
+	null    bool
+}
+
+// Makes a present `Optional` with the value `v`.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, present: true}
+}
+
+// Makes a present `Optional` which is `null`.
+func Null[T any]() Optional[T] {
+	return Optional[T]{present: true, null: true}
+}
+
+// Whether the field was present in the input, even if it was `null`.
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+// Whether the field was `null` in the input.
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// Returns the value and whether it was present and not `null`.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.present && !o.null
+}
+
+// Sets the value, making it present and not `null`.
+func (o *Optional[T]) Set(v T) {
+	o.Value = v
+	o.present = true
+	o.null = false
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	// Only ever called when the key is in the JSON.
+	o.present = true
+	o.null = bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+
+	if o.null {
+		var zero T
+		o.Value = zero
+		return nil
+	}
+
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Absent and `null` both become `null`, there is no way to leave out an
+// absent one with `omitempty`.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.present || o.null {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.Value)
+}
@@ -0,0 +1,153 @@
+package vx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type userPatch struct {
+	Name  Optional[string] `vx:"name=name, notNull, minLength=3"`
+	Email Optional[string] `vx:"name=email, present"`
+	Age   Optional[any]    `vx:"name=age, type=int"`
+	Bio   Optional[string] `vx:"name=bio, required"`
+}
+
+func decodeUserPatch(t *testing.T, data string) userPatch {
+	var u userPatch
+
+	if err := json.Unmarshal([]byte(data), &u); err != nil {
+		t.Fatal(err)
+	}
+
+	return u
+}
+
+func TestOptional(t *testing.T) {
+	tests := []validateStructTest{
+		{
+			name: "userPatch with everything should not give an error",
+			arg:  decodeUserPatch(t, `{"name": "abc", "email": null, "age": 3, "bio": "hi"}`),
+			want: want{true, 0},
+		},
+		{
+			name: "userPatch without email and bio should give errors",
+			arg:  decodeUserPatch(t, `{"name": "abc"}`),
+			want: want{true, 2},
+		},
+		{
+			name: "userPatch with null name and bio should give errors",
+			arg:  decodeUserPatch(t, `{"name": null, "email": "a@b.c", "bio": null}`),
+			want: want{true, 2},
+		},
+		{
+			name: "userPatch with short name and fractional age should give errors",
+			arg:  decodeUserPatch(t, `{"name": "ab", "email": "a@b.c", "age": 2.5, "bio": "hi"}`),
+			want: want{true, 2},
+		},
+		{
+			name: "userPatch made with Some and Null should not give an error",
+			arg: userPatch{
+				Name:  Some("abc"),
+				Email: Null[string](),
+				Bio:   Some("hi"),
+			},
+			want: want{true, 0},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+
+	t.Run("Optional should tell apart absent, null and a value", func(t *testing.T) {
+		u := decodeUserPatch(t, `{"name": "abc", "email": null}`)
+
+		if v, ok := u.Name.Get(); !ok || v != "abc" {
+			t.Errorf("expected name to be abc but got %v, %v", v, ok)
+		}
+
+		if !u.Email.IsPresent() || !u.Email.IsNull() {
+			t.Errorf("expected email to be present and null but got %v, %v", u.Email.IsPresent(), u.Email.IsNull())
+		}
+
+		if u.Age.IsPresent() || u.Age.IsNull() {
+			t.Errorf("expected age to be absent but got %v, %v", u.Age.IsPresent(), u.Age.IsNull())
+		}
+	})
+
+	t.Run("Optional should marshal to its value or null", func(t *testing.T) {
+		data, err := json.Marshal(userPatch{Name: Some("abc"), Email: Null[string]()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := `{"Name":"abc","Email":null,"Age":null,"Bio":null}`
+		if string(data) != expected {
+			t.Errorf("expected %s but got %s", expected, data)
+		}
+	})
+
+	t.Run("default should be set on an absent Optional", func(t *testing.T) {
+		type withDefault struct {
+			Limit Optional[int] `vx:"default=20"`
+		}
+
+		w := withDefault{}
+		ValidateStruct(&w)
+
+		if v, ok := w.Limit.Get(); !ok || v != 20 {
+			t.Errorf("expected limit to be 20 but got %v, %v", v, ok)
+		}
+	})
+
+	t.Run("pointer to Optional should be validated like an Optional", func(t *testing.T) {
+		type pointerPatch struct {
+			Name  *Optional[string] `json:"name,omitempty" vx:"name=name, minLength=3"`
+			Bio   *Optional[string] `json:"bio,omitempty" vx:"name=bio, present"`
+			Limit *Optional[int]    `json:"limit,omitempty" vx:"default=20"`
+		}
+
+		short := Some("ab")
+		res, ok := ValidateStruct(pointerPatch{Name: &short})
+		if !ok || len(res.Errors) != 2 {
+			t.Errorf("expected the minLength and present errors but got %v, %v", ok, res.Errors)
+		}
+
+		bio := Some("hi")
+		p := pointerPatch{Bio: &bio}
+		res, ok = ValidateStruct(&p)
+		if !ok || len(res.Errors) != 0 {
+			t.Errorf("expected no errors for a nil name but got %v, %v", ok, res.Errors)
+		}
+
+		if p.Limit == nil {
+			t.Fatalf("expected limit to be set to its default")
+		}
+
+		if v, ok := p.Limit.Get(); !ok || v != 20 {
+			t.Errorf("expected limit to be 20 but got %v, %v", v, ok)
+		}
+
+		res, ok = ValidateJSON(SchemaOf[pointerPatch](), []byte(`{"name": "ab"}`))
+		if !ok || len(res.Errors) != 2 {
+			t.Errorf("expected the minLength and present errors for the document but got %v, %v", ok, res.Errors)
+		}
+	})
+
+	t.Run("notNull on a pointer to Optional should give an internal error", func(t *testing.T) {
+		type pointerNotNull struct {
+			Age *Optional[int] `json:"age" vx:"name=age, notNull"`
+		}
+
+		var p pointerNotNull
+		if err := json.Unmarshal([]byte(`{"age": null}`), &p); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := ValidateStruct(p); ok {
+			t.Errorf("expected an internal error for the struct")
+		}
+
+		if _, ok := ValidateJSON(SchemaOf[pointerNotNull](), []byte(`{"age": null}`)); ok {
+			t.Errorf("expected an internal error for the document")
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package vx
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Changes how a single call to `ValidateStruct` behaves.
+//
+// Ex:
+// res, ok := vx.ValidateStruct(&u, vx.WithWriteBack())
+type Option func(*options)
+
+type options struct {
+	writeBack bool
+	coerce    bool
+	ctx       context.Context
+	groups    []string
+	// Names of the fields to validate, all when empty.
+	only []string
+	// Names of the fields not to validate.
+	except []string
+	// Stop after these many errors, no limit when 0.
+	maxErrors          int
+	firstErrorPerField bool
+	// Names of the keys that `Decode` shouldn't report as unknown.
+	allowedUnknown []string
+	// Locales of the messages by preference, normalized.
+	locales []string
+	// Name the types in the messages the way JSON Schema does.
+	jsonTypes bool
+	labels    LabelResolver
+}
+
+func makeOptions(opts []Option) options {
+	o := options{
+		ctx: context.Background(),
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Assigns the values converted during the type checks, like a JSON number
+// given to a field with "type=int", back into the struct. The struct must be
+// passed as a pointer for this to work.
+func WithWriteBack() Option {
+	return func(o *options) {
+		o.writeBack = true
+	}
+}
+
+// Parses string values into the type in the tag before the rules run, for the
+// input which only ever has strings like query params, form values or CSV.
+// A value that can't be parsed is reported as a type error.
+//
+// Parsed values are assigned back into the struct if it was passed as a
+// pointer, there is no need for `WithWriteBack`.
+func WithCoercion() Option {
+	return func(o *options) {
+		o.coerce = true
+	}
+}
+
+// The context given to `ContextValidator`s, `context.Background()` otherwise.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// Selects the validation groups whose rules should be executed, like "create"
+// for the rules with "@create" in the tag, e.g. "required@create". Rules
+// without a group are always executed, the ones with a group only when one
+// of their groups is selected.
+//
+// The groups must be registered with `RegisterGroups`. Selecting a group that
+// isn't, like a typo such as "creat", is reported as an internal error. A
+// registered group that no rule of the struct is in just doesn't run any rule.
+func WithGroups(groups ...string) Option {
+	return func(o *options) {
+		o.groups = append(o.groups, groups...)
+	}
+}
+
+var registeredGroups = struct {
+	sync.RWMutex
+	names map[string]bool
+}{
+	names: map[string]bool{},
+}
+
+// Registers the names of the validation groups, the only ones that can be in
+// the tags and in `WithGroups`. Registering a group again does nothing.
+//
+// Ex:
+// vx.RegisterGroups("create", "update", "list")
+func RegisterGroups(groups ...string) {
+	registeredGroups.Lock()
+	defer registeredGroups.Unlock()
+
+	for _, group := range groups {
+		registeredGroups.names[group] = true
+	}
+}
+
+func isRegisteredGroup(group string) bool {
+	registeredGroups.RLock()
+	defer registeredGroups.RUnlock()
+
+	return registeredGroups.names[group]
+}
+
+// Validates only the fields with the given names, the same names that are in
+// the errors like "ComplexA.simple_a". The name of a nested struct selects all
+// of its fields.
+//
+// Cross field rules and field groups are only executed when all the fields
+// they refer to are selected. A `Validator` is only called when its struct is
+// selected as a whole, which is never the case for the root struct.
+//
+// Names that don't belong to any field are reported as an internal error.
+func OnlyFields(names ...string) Option {
+	return func(o *options) {
+		o.only = append(o.only, names...)
+	}
+}
+
+// Opposite of `OnlyFields`, validates everything except the fields with the
+// given names.
+func ExceptFields(names ...string) Option {
+	return func(o *options) {
+		o.except = append(o.except, names...)
+	}
+}
+
+// Whether the field or struct with the full `name` is selected by
+// `OnlyFields` and `ExceptFields`.
+func (o options) selected(name string) bool {
+	if len(o.only) > 0 && !matchesAny(name, o.only) {
+		return false
+	}
+
+	return !matchesAny(name, o.except)
+}
+
+// Whether `name` is one of `names` or is nested in one of them.
+func matchesAny(name string, names []string) bool {
+	for _, n := range names {
+		if name == n || strings.HasPrefix(name, n+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Stops the validation after the first error, for when only a yes or no is
+// needed. Same as `MaxErrors(1)`.
+func FailFast() Option {
+	return MaxErrors(1)
+}
+
+// Stops the validation after `n` errors, `VxResult.Truncated` tells whether
+// it did stop. No limit if `n` is 0 or less. Only for the errors of the
+// validation itself, internal errors are always reported.
+func MaxErrors(n int) Option {
+	return func(o *options) {
+		o.maxErrors = n
+	}
+}
+
+// Stops validating a field, or a struct for its field groups and `Validator`,
+// after its first error.
+func FirstErrorPerField() Option {
+	return func(o *options) {
+		o.firstErrorPerField = true
+	}
+}
+
+// Lets `Decode` accept the keys with the given names even though they aren't
+// fields of the struct, like "meta" or "address.extra". The name of a key
+// allows everything nested in it as well.
+func AllowUnknownFields(names ...string) Option {
+	return func(o *options) {
+		o.allowedUnknown = append(o.allowedUnknown, names...)
+	}
+}
+
+// The locales of the messages by preference, like "pt-BR" and then "en". An
+// error is given the message of the first locale whose `Catalog` has one for
+// its code, "pt-br" being looked for before "pt". Errors without one in any
+// of the catalogs keep the default message.
+func WithLocale(locales ...string) Option {
+	return func(o *options) {
+		for _, locale := range locales {
+			o.locales = append(o.locales, normalizeLocale(locale))
+		}
+	}
+}
+
+// Same as `WithLocale` with the locales of an Accept-Language header, in the
+// order of their quality values.
+//
+// Ex:
+//
+//	res, ok := vx.Decode(req.Body, &u, vx.WithAcceptLanguage(req.Header.Get("Accept-Language")))
+func WithAcceptLanguage(header string) Option {
+	return func(o *options) {
+		o.locales = append(o.locales, parseAcceptLanguage(header)...)
+	}
+}
+
+// Names the types in the type errors the way JSON Schema does, like "number"
+// for float64, "integer" for int, "array of string" for []string and "object"
+// for maps, for the APIs whose clients don't know about Go. The same names
+// are in the "expected" and "actual" params of the errors.
+//
+// Ex:
+//
+//	age should be of type number but got string
+func WithJSONTypeNames() Option {
+	return func(o *options) {
+		o.jsonTypes = true
+	}
+}
+
+// Gives the labels of the fields for the messages with `resolve`, like from a
+// translation table or `HumanizeLabel`. The `Field` and `Path` of the errors
+// stay the same.
+func WithLabels(resolve LabelResolver) Option {
+	return func(o *options) {
+		o.labels = resolve
+	}
+}
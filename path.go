@@ -0,0 +1,41 @@
+package vx
+
+import (
+	"errors"
+)
+
+// Renders a `Path` as a string, for the notations other than the ones vx has
+// already, `JSONPointer` and `DottedPath`.
+//
+// Ex:
+//
+//	upper := func(p vx.Path) string {
+//		return strings.ToUpper(p.String())
+//	}
+type PathFormatter func(Path) string
+
+var (
+	// Renders a path as an RFC 6901 JSON Pointer, like "/complexA/simple_a/3".
+	JSONPointer PathFormatter = Path.JSONPointer
+	// Renders a path in the dotted-bracket form, like "complexA.simple_a[3]".
+	DottedPath PathFormatter = Path.String
+)
+
+// Gives the paths of all the errors, rendered by `format` and in the same
+// order as `Errors`. An error which isn't a `*FieldError`, like an internal
+// one, doesn't have a path and gives "".
+func (v VxResult) Paths(format PathFormatter) []string {
+	paths := []string{}
+
+	for _, err := range v.Errors {
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			paths = append(paths, "")
+			continue
+		}
+
+		paths = append(paths, format(fieldErr.Path))
+	}
+
+	return paths
+}
@@ -0,0 +1,92 @@
+package vx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	type simple struct {
+		SimpleA any `vx:"name=simple_a, type=string"`
+	}
+
+	type Embedded struct {
+		Flat any `json:"flat" vx:"type=int"`
+	}
+
+	type complex struct {
+		Embedded
+		ComplexA simple `json:"complexA"`
+		ComplexB simple
+		Tags     []int `json:"tags" vx:"name=tags"`
+	}
+
+	t.Run("paths should use vx names, then json names, then Go names", func(t *testing.T) {
+		res, _ := ValidateStruct(complex{
+			Embedded: Embedded{Flat: "a"},
+			ComplexA: simple{SimpleA: 1},
+			ComplexB: simple{SimpleA: 2},
+		})
+
+		expected := []string{"/flat", "/complexA/simple_a", "/ComplexB/simple_a"}
+		if got := res.Paths(JSONPointer); strings.Join(got, " ") != strings.Join(expected, " ") {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("paths should have the indices of the objects in an array", func(t *testing.T) {
+		res, _ := ValidateJSON(SchemaOf[docOrder](), []byte(`{"id": "a1", "address": {"city": "Pune"}, "items": [{"sku": "abc", "quantity": 1}, {"sku": "ab", "quantity": 1}]}`))
+
+		expected := []string{"items[1].sku"}
+		if got := res.Paths(DottedPath); strings.Join(got, " ") != strings.Join(expected, " ") {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("unknown keys should be escaped", func(t *testing.T) {
+		var s decodeSearch
+		res, _ := Decode(strings.NewReader(`{"query": "vx", "filter": {"kind": "a", "a/b~c": 1, "a.b": 2}}`), &s)
+
+		expected := []string{"/filter/a.b", "/filter/a~1b~0c"}
+		if got := res.Paths(JSONPointer); strings.Join(got, " ") != strings.Join(expected, " ") {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+
+		expected = []string{`filter["a.b"]`, "filter.a/b~c"}
+		if got := res.Paths(DottedPath); strings.Join(got, " ") != strings.Join(expected, " ") {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("custom formatter should be used", func(t *testing.T) {
+		res, _ := ValidateValue("", "name=id, required")
+
+		upper := func(p Path) string {
+			return strings.ToUpper(p.String())
+		}
+
+		if got := res.Paths(upper); len(got) != 1 || got[0] != "ID" {
+			t.Errorf("expected [ID] but got %v", got)
+		}
+	})
+
+	t.Run("internal errors should not have a path", func(t *testing.T) {
+		res, _ := ValidateValue("", "type=nope")
+
+		if got := res.Paths(JSONPointer); len(got) != 1 || got[0] != "" {
+			t.Errorf("expected an empty path but got %v", got)
+		}
+	})
+
+	t.Run("Path should render indices and keys", func(t *testing.T) {
+		p := Path{}.Key("lines").Index(0).Key("city")
+
+		if got := p.JSONPointer(); got != "/lines/0/city" {
+			t.Errorf("expected /lines/0/city but got %s", got)
+		}
+
+		if got := p.String(); got != "lines[0].city" {
+			t.Errorf("expected lines[0].city but got %s", got)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package vx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The media type of a `Problem`.
+const ProblemContentType = "application/problem+json"
+
+// An RFC 7807 problem details document for a failed validation, made with
+// `NewProblem`. Every error is an entry in `InvalidParams`.
+//
+// Ex:
+//
+//	{
+//		"type": "about:blank",
+//		"title": "Bad Request",
+//		"status": 400,
+//		"detail": "2 validation errors",
+//		"invalid-params": [
+//			{"path": "/name", "code": "minLength", "message": "..."},
+//			{"path": "", "code": "exclusive", "message": "...", "params": {"fields": ["assoc", "bonus"]}}
+//		]
+//	}
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params"`
+	// Same as `VxResult.Truncated`, left out when false.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// An error in a `Problem`.
+type InvalidParam struct {
+	// Path of the field, rendered as a JSON Pointer unless `ProblemPaths`
+	// says otherwise.
+	Path    string         `json:"path"`
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// Changes how `NewProblem` makes a `Problem`.
+type ProblemOption func(*problemOptions)
+
+type problemOptions struct {
+	typeURI  string
+	title    string
+	status   int
+	instance string
+	format   PathFormatter
+}
+
+// The "type" of the problem, a URI documenting it. "about:blank" otherwise.
+func ProblemType(uri string) ProblemOption {
+	return func(o *problemOptions) {
+		o.typeURI = uri
+	}
+}
+
+// The "title" of the problem, the text of the status otherwise.
+func ProblemTitle(title string) ProblemOption {
+	return func(o *problemOptions) {
+		o.title = title
+	}
+}
+
+// The HTTP status of the problem, 400 otherwise.
+func ProblemStatus(status int) ProblemOption {
+	return func(o *problemOptions) {
+		o.status = status
+	}
+}
+
+// The "instance" of the problem, a URI for this occurrence of it, like the
+// path of the request.
+func ProblemInstance(uri string) ProblemOption {
+	return func(o *problemOptions) {
+		o.instance = uri
+	}
+}
+
+// Renders the paths of the errors with `format`, `JSONPointer` otherwise.
+func ProblemPaths(format PathFormatter) ProblemOption {
+	return func(o *problemOptions) {
+		o.format = format
+	}
+}
+
+// Makes a `Problem` out of the result of a validation, as given by
+// `ValidateStruct` and the like.
+//
+// An internal error, with `ok` as false, is a bug on the server and not in
+// the request. The problem is then a 500 without the errors in it, so that
+// nothing about the server is given away.
+func NewProblem(res VxResult, ok bool, opts ...ProblemOption) Problem {
+	o := problemOptions{
+		typeURI: "about:blank",
+		status:  http.StatusBadRequest,
+		format:  JSONPointer,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if !ok {
+		o.status = http.StatusInternalServerError
+	}
+
+	if o.title == "" {
+		o.title = http.StatusText(o.status)
+	}
+
+	p := Problem{
+		Type:          o.typeURI,
+		Title:         o.title,
+		Status:        o.status,
+		Instance:      o.instance,
+		InvalidParams: []InvalidParam{},
+	}
+
+	if !ok {
+		return p
+	}
+
+	for _, err := range res.Errors {
+		param := InvalidParam{Message: err.Error()}
+
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			param.Path = o.format(fieldErr.Path)
+			param.Code = fieldErr.Code
+			param.Params = fieldErr.Params
+		}
+
+		p.InvalidParams = append(p.InvalidParams, param)
+	}
+
+	switch n := len(p.InvalidParams); n {
+	case 0:
+	case 1:
+		p.Detail = "1 validation error"
+	default:
+		p.Detail = fmt.Sprintf("%d validation errors", n)
+	}
+
+	p.Truncated = res.Truncated
+
+	return p
+}
+
+// Writes the problem as the response with its status.
+func (p Problem) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	w.Write(data)
+}
+
+// Gives an `http.Handler` which responds with the `Problem` of the result.
+//
+// Ex:
+//
+//	res, ok := vx.Decode(req.Body, &u)
+//	if !ok || len(res.Errors) > 0 {
+//		vx.ProblemHandler(res, ok, vx.ProblemInstance(req.URL.Path)).ServeHTTP(w, req)
+//		return
+//	}
+func ProblemHandler(res VxResult, ok bool, opts ...ProblemOption) http.Handler {
+	return NewProblem(res, ok, opts...)
+}
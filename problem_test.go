@@ -0,0 +1,84 @@
+package vx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProblem(t *testing.T) {
+	type search struct {
+		_     struct{} `vx:"exclusive=assoc bonus"`
+		Name  any      `json:"name" vx:"name=name, type=string, minLength=3"`
+		Assoc any      `json:"assoc" vx:"name=assoc"`
+		Bonus any      `json:"bonus" vx:"name=bonus"`
+	}
+
+	t.Run("errors should be the invalid params", func(t *testing.T) {
+		res, ok := ValidateStruct(search{Name: "ab", Assoc: "a", Bonus: "b"})
+		p := NewProblem(res, ok, ProblemInstance("/search"))
+
+		if p.Status != http.StatusBadRequest || p.Title != "Bad Request" || p.Type != "about:blank" || p.Instance != "/search" {
+			t.Errorf("expected a 400 problem for /search but got %+v", p)
+		}
+
+		if p.Detail != "2 validation errors" || len(p.InvalidParams) != 2 {
+			t.Fatalf("expected 2 invalid params but got %+v", p)
+		}
+
+		name, group := p.InvalidParams[0], p.InvalidParams[1]
+
+		if name.Path != "/name" || name.Code != "minLength" || name.Message != res.Errors[0].Error() {
+			t.Errorf("expected the minLength error on /name but got %+v", name)
+		}
+
+		if group.Path != "" || group.Code != "exclusive" || group.Params["fields"] == nil {
+			t.Errorf("expected the exclusive error on the root but got %+v", group)
+		}
+	})
+
+	t.Run("options should change the problem", func(t *testing.T) {
+		res, ok := ValidateStruct(search{Name: "ab"})
+		p := NewProblem(res, ok, ProblemType("https://example.com/invalid"), ProblemTitle("Invalid search"), ProblemStatus(http.StatusUnprocessableEntity), ProblemPaths(DottedPath))
+
+		if p.Type != "https://example.com/invalid" || p.Title != "Invalid search" || p.Status != http.StatusUnprocessableEntity {
+			t.Errorf("expected the options to be used but got %+v", p)
+		}
+
+		if len(p.InvalidParams) != 1 || p.InvalidParams[0].Path != "name" {
+			t.Errorf("expected a dotted path but got %+v", p.InvalidParams)
+		}
+	})
+
+	t.Run("internal errors should not be given away", func(t *testing.T) {
+		res, ok := ValidateValue("", "type=nope")
+		p := NewProblem(res, ok)
+
+		if p.Status != http.StatusInternalServerError || len(p.InvalidParams) != 0 {
+			t.Errorf("expected a 500 problem without the errors but got %+v", p)
+		}
+	})
+
+	t.Run("handler should write problem+json", func(t *testing.T) {
+		res, ok := ValidateStruct(search{Name: "ab"})
+
+		rec := httptest.NewRecorder()
+		ProblemHandler(res, ok).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/search", nil))
+
+		if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != ProblemContentType {
+			t.Errorf("expected a 400 with %s but got %d with %s", ProblemContentType, rec.Code, rec.Header().Get("Content-Type"))
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+
+		params, _ := body["invalid-params"].([]any)
+		if len(params) != 1 || !strings.Contains(rec.Body.String(), `"code":"minLength"`) {
+			t.Errorf("expected a single invalid param but got %s", rec.Body.String())
+		}
+	})
+}
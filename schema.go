@@ -0,0 +1,118 @@
+package vx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"vx/internal"
+)
+
+// The rules in the tags of a struct type, made once so that JSON documents can
+// be validated against them directly with `ValidateMap` and `ValidateJSON`,
+// without decoding the documents into the struct first.
+type Schema struct {
+	doc *internal.DocSchema
+	// Errors in the tags of the struct, given back every time the schema is
+	// used.
+	errs []error
+}
+
+var schemaCache = struct {
+	sync.RWMutex
+	schemas map[reflect.Type]*Schema
+}{
+	schemas: map[reflect.Type]*Schema{},
+}
+
+// Gives the `Schema` of the struct `T`. It's made the first time it's asked
+// for and cached after that.
+//
+// Ex:
+// res, ok := vx.ValidateJSON(vx.SchemaOf[user](), body)
+func SchemaOf[T any]() *Schema {
+	return schemaFor(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Same as `SchemaOf` for the type `t`.
+func schemaFor(t reflect.Type) *Schema {
+	schemaCache.RLock()
+	schema, ok := schemaCache.schemas[t]
+	schemaCache.RUnlock()
+
+	if ok {
+		return schema
+	}
+
+	doc, errs := internal.MakeDocSchema(t)
+	schema = &Schema{doc: doc, errs: errs}
+
+	schemaCache.Lock()
+	defer schemaCache.Unlock()
+
+	schemaCache.schemas[t] = schema
+
+	return schema
+}
+
+// Validates the document `m`, like a JSON object decoded into `any`, against
+// the `schema` of a struct, without decoding `m` into the struct. A value of
+// the wrong type is reported as it is instead of failing the decoding, like a
+// string in a field which should be a number.
+//
+// A field is found in `m` by its name in the "json" tag, or by the name of the
+// field in the struct otherwise, with the case ignored if it doesn't match as
+// it is. A field without a "type=" in its tag should have the type it has in
+// the struct, where a nested struct is an object.
+//
+// It goes further than `ValidateStruct`, `Decode` and `Unmarshal` in a couple
+// of ways, so the errors are not always the same:
+//   - The objects in arrays are validated as nested structs, named like
+//     "items[0].price". `ValidateStruct` doesn't look inside slices.
+//   - There is no struct to call the `Validator`s on, so they are not called.
+//     Neither is there anything to write back to or to fill with "default=".
+func ValidateMap(schema *Schema, m map[string]any, opts ...Option) (res VxResult, ok bool) {
+	res = VxResult{
+		Errors: []error{},
+	}
+
+	if len(schema.errs) > 0 {
+		res.Errors = append(res.Errors, schema.errs...)
+		return res, false
+	}
+
+	return validateParsed(schema.doc.Parse(m), makeOptions(opts), res, true)
+}
+
+// Same as `ValidateMap` for the JSON object in `data`. Numbers are decoded as
+// `json.Number` so that big integers are validated as they were sent.
+//
+// A `data` which isn't a JSON object is reported as an error with the code
+// "invalidJSON", with `ok` as true as it's the document that is invalid and
+// not the schema.
+func ValidateJSON(schema *Schema, data []byte, opts ...Option) (res VxResult, ok bool) {
+	var m map[string]any
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	err := d.Decode(&m)
+	if err == nil && m == nil {
+		err = fmt.Errorf("expected an object, received null")
+	}
+
+	if err == nil {
+		if _, extra := d.Token(); extra != io.EOF {
+			err = fmt.Errorf("expected a single object, received more after it")
+		}
+	}
+
+	if err != nil {
+		res.Errors = []error{invalidJSON(err, makeOptions(opts))}
+		return res, true
+	}
+
+	return ValidateMap(schema, m, opts...)
+}
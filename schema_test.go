@@ -0,0 +1,179 @@
+package vx
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+type docItem struct {
+	SKU      string `json:"sku" vx:"name=sku, required, minLength=3"`
+	Quantity int    `json:"quantity" vx:"name=quantity, required"`
+}
+
+type docAddress struct {
+	City string `json:"city" vx:"name=city, required"`
+}
+
+type docOrder struct {
+	ID       any              `json:"id" vx:"name=id, type=string, required"`
+	Email    string           `json:"email" vx:"name=email, notBlank"`
+	Note     Optional[string] `json:"note" vx:"name=note, notNull"`
+	Address  docAddress       `json:"address" vx:"name=address"`
+	Billing  *docAddress      `json:"billing" vx:"name=billing"`
+	Items    []docItem        `json:"items" vx:"name=items, required"`
+	Tags     []string         `json:"tags" vx:"name=tags"`
+	Placed   time.Time        `json:"placed" vx:"name=placed"`
+	Internal string           `json:"-" vx:"required"`
+}
+
+func TestValidateJSON(t *testing.T) {
+	schema := SchemaOf[docOrder]()
+
+	tests := []struct {
+		name string
+		arg  string
+		want want
+	}{
+		{
+			name: "valid order should not give an error",
+			arg:  `{"id": "a1", "email": "a@b.c", "address": {"city": "Pune"}, "items": [{"sku": "abc", "quantity": 2}], "tags": ["x"], "placed": "2024-01-02T15:04:05Z"}`,
+			want: want{true, 0},
+		},
+		{
+			name: "keys in another case should be found",
+			arg:  `{"ID": "a1", "Address": {"City": "Pune"}, "Items": [{"SKU": "abc", "Quantity": 2}]}`,
+			want: want{true, 0},
+		},
+		{
+			name: "empty object should give errors for the required fields",
+			arg:  `{}`,
+			want: want{true, 3},
+		},
+		{
+			name: "wrong types should give type errors",
+			arg:  `{"id": 1, "email": 2, "address": {"city": "Pune"}, "items": [{"sku": "abc", "quantity": 2.5}], "tags": [1], "placed": "yesterday"}`,
+			want: want{true, 5},
+		},
+		{
+			name: "nested objects in arrays should be validated",
+			arg:  `{"id": "a1", "address": {"city": "Pune"}, "items": [{"sku": "abc", "quantity": 1}, {"sku": "ab"}]}`,
+			want: want{true, 2},
+		},
+		{
+			name: "object instead of an array should give an error",
+			arg:  `{"id": "a1", "address": {"city": "Pune"}, "items": {"sku": "abc"}}`,
+			want: want{true, 1},
+		},
+		{
+			name: "null Optional and billing without city should give errors",
+			arg:  `{"id": "a1", "note": null, "address": {"city": "Pune"}, "billing": {}, "items": [{"sku": "abc", "quantity": 1}]}`,
+			want: want{true, 2},
+		},
+		{
+			name: "array instead of an object should give an error",
+			arg:  `[]`,
+			want: want{true, 1},
+		},
+		{
+			name: "invalid JSON should give an error",
+			arg:  `{"id": `,
+			want: want{true, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, ok := ValidateJSON(schema, []byte(test.arg))
+
+			if ok != test.want.ok {
+				t.Error(res.String())
+				t.Errorf("expected ok to be %v but got %v, check the errors above", test.want.ok, ok)
+			}
+
+			if len(res.Errors) != test.want.count {
+				t.Error(res.String())
+				t.Errorf("expected to get exactly %v number of validation errors but got %v, check the errors above.", test.want.count, len(res.Errors))
+			}
+		})
+	}
+}
+
+func TestValidateMap(t *testing.T) {
+	t.Run("map decoded by encoding/json should give the same errors as the struct", func(t *testing.T) {
+		type user struct {
+			Name    any        `json:"name" vx:"name=name, type=string, minLength=3"`
+			Email   string     `json:"email" vx:"name=email, notBlank"`
+			Age     int        `json:"age" vx:"name=age, required(nonZero)"`
+			Address docAddress `json:"address" vx:"name=address"`
+		}
+
+		data := `{"name": "ab", "email": " ", "address": {"city": ""}}`
+
+		var m map[string]any
+		if err := json.Unmarshal([]byte(data), &m); err != nil {
+			t.Fatal(err)
+		}
+
+		var u user
+		if err := json.Unmarshal([]byte(data), &u); err != nil {
+			t.Fatal(err)
+		}
+
+		mapRes, mapOk := ValidateMap(SchemaOf[user](), m)
+		structRes, structOk := ValidateStruct(u)
+
+		if !mapOk || !structOk || mapRes.String() != structRes.String() {
+			t.Errorf("expected the same errors but got %v and %v", mapRes.StringArray(), structRes.StringArray())
+		}
+	})
+
+	t.Run("schema with a broken tag should give an internal error every time", func(t *testing.T) {
+		type broken struct {
+			A string `vx:"type=int"`
+		}
+
+		for i := 0; i < 2; i++ {
+			res, ok := ValidateMap(SchemaOf[broken](), map[string]any{"A": "a"})
+			if ok || len(res.Errors) != 1 {
+				t.Errorf("expected a single internal error but got %v, %v", ok, res.StringArray())
+			}
+		}
+	})
+
+	t.Run("field groups should be checked on the objects", func(t *testing.T) {
+		type contact struct {
+			_     struct{} `vx:"atLeastOne=email phone"`
+			Email any      `json:"email" vx:"name=email"`
+			Phone any      `json:"phone" vx:"name=phone"`
+		}
+
+		res, ok := ValidateMap(SchemaOf[contact](), map[string]any{})
+		if !ok || len(res.Errors) != 1 {
+			t.Errorf("expected a single error but got %v, %v", ok, res.StringArray())
+		}
+	})
+
+	t.Run("absent keys should not be logged", func(t *testing.T) {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		t.Cleanup(func() {
+			log.SetOutput(os.Stderr)
+		})
+
+		ValidateMap(SchemaOf[docOrder](), map[string]any{})
+
+		if buf.Len() > 0 {
+			t.Errorf("expected nothing to be logged but got %s", buf.String())
+		}
+	})
+
+	t.Run("SchemaOf should give the same schema every time", func(t *testing.T) {
+		if SchemaOf[docOrder]() != SchemaOf[docOrder]() {
+			t.Error("expected the schema to be cached")
+		}
+	})
+}
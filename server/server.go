@@ -23,42 +23,16 @@ type user struct {
 func test(w http.ResponseWriter, req *http.Request) {
 	var u user
 
-	err := json.NewDecoder(req.Body).Decode(&u)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	res, ok := vx.ValidateStruct(u)
+	res, ok := vx.Decode(req.Body, &u, vx.WithAcceptLanguage(req.Header.Get("Accept-Language")))
 	fmt.Println("ok:", ok)
 	fmt.Println("res:", res)
 
-	w.Header().Set("Content-Type", "application/json")
-	jsonRes := make(map[string]interface{})
-
-	if !ok {
-		jsonRes["errors"] = res.StringArray()
-		data, err := json.Marshal(jsonRes)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
-
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write(data)
+	if !ok || len(res.Errors) > 0 {
+		vx.ProblemHandler(res, ok, vx.ProblemInstance(req.URL.Path)).ServeHTTP(w, req)
 		return
 	}
 
-	if len(res.Errors) > 0 {
-		jsonRes["errors"] = res.StringArray()
-		data, err := json.Marshal(jsonRes)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
-
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write(data)
-		return
-	}
+	w.Header().Set("Content-Type", "application/json")
 
 	// fmt.Fprintf(w, "User: %+v", u)
 
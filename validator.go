@@ -0,0 +1,100 @@
+package vx
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"vx/internal"
+)
+
+// Implemented by the structs with invariants that can't be written as rules
+// in the tag. `ValidateStruct` calls it on every struct it visits, nested ones
+// included, after running the rules in the tags.
+//
+// The returned errors are reported on the struct. A returned `*FieldError`
+// is reported on its `Field` instead, relative to the struct, so that an
+// error can still point at one of the fields.
+type Validator interface {
+	VxValidate() []error
+}
+
+// Same as `Validator` but with the context given by `WithContext`. It's
+// called instead of `VxValidate` if a struct has both.
+type ContextValidator interface {
+	VxValidateContext(ctx context.Context) []error
+}
+
+var (
+	validatorType        = reflect.TypeOf((*Validator)(nil)).Elem()
+	contextValidatorType = reflect.TypeOf((*ContextValidator)(nil)).Elem()
+)
+
+// Whether the struct has a `Validator` or `ContextValidator` to call.
+func hasValidator(info internal.VxStructInfo) bool {
+	if !info.Value.IsValid() {
+		return false
+	}
+
+	ptr := reflect.PointerTo(info.Type)
+	return ptr.Implements(validatorType) || ptr.Implements(contextValidatorType)
+}
+
+// Calls the `Validator` or `ContextValidator` of the struct, if it has one,
+// and gives back its errors as `*FieldError`s.
+func execValidator(info internal.VxStructInfo, o options) []error {
+	v := info.Value
+
+	// There is no struct to call it on when validating a document.
+	if !v.IsValid() {
+		return nil
+	}
+
+	// Methods with a pointer receiver can only be called on a pointer. If
+	// the struct wasn't given as a pointer, we call them on a copy instead.
+	if !v.CanAddr() {
+		v = reflect.New(info.Type).Elem()
+		v.Set(info.Value)
+	}
+
+	var errs []error
+
+	switch s := v.Addr().Interface().(type) {
+	case ContextValidator:
+		errs = s.VxValidateContext(o.ctx)
+	case Validator:
+		errs = s.VxValidate()
+	default:
+		return nil
+	}
+
+	fieldErrs := []error{}
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if fieldErr, ok := err.(*FieldError); ok {
+			e := *fieldErr
+			e.Field = strings.TrimPrefix(info.Name+"."+e.Field, ".")
+			e.Field = strings.TrimSuffix(e.Field, ".")
+
+			if len(e.Path) == 0 {
+				e.Path = internal.ParsePath(fieldErr.Field)
+			}
+
+			e.Path = info.Path.Join(e.Path)
+
+			if e.Code == "" {
+				e.Code = "vxValidate"
+			}
+
+			fieldErrs = append(fieldErrs, &e)
+			continue
+		}
+
+		fieldErrs = append(fieldErrs, &FieldError{Field: info.Name, Path: info.Path, Code: "vxValidate", Err: err})
+	}
+
+	return fieldErrs
+}
@@ -0,0 +1,105 @@
+package vx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type priceRange struct {
+	Min any `vx:"name=min, type=int"`
+	Max any `vx:"name=max, type=int"`
+}
+
+func (p *priceRange) VxValidate() []error {
+	min, _ := p.Min.(int)
+	max, _ := p.Max.(int)
+
+	if max-min > 100 {
+		return []error{&FieldError{Field: "max", Err: errors.New("max can be at most 100 more than min")}}
+	}
+
+	return nil
+}
+
+type ctxKey struct{}
+
+type order struct {
+	Status any `vx:"name=status, type=string, required"`
+	Price  priceRange
+}
+
+func (o order) VxValidateContext(ctx context.Context) []error {
+	if o.Status == "shipped" && ctx.Value(ctxKey{}) != "admin" {
+		return []error{errors.New("only an admin can ship an order"), nil}
+	}
+
+	return nil
+}
+
+func TestValidator(t *testing.T) {
+	admin := context.WithValue(context.Background(), ctxKey{}, "admin")
+
+	tests := []validateStructTest{
+		{
+			name: "order with valid values should not give an error",
+			arg: order{
+				Status: "open",
+				Price:  priceRange{Min: 1, Max: 10},
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "order with a wide price range should give an error",
+			arg: order{
+				Status: "open",
+				Price:  priceRange{Min: 1, Max: 1000},
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "shipped order without admin should give an error",
+			arg: &order{
+				Status: "shipped",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "shipped order with admin should not give an error",
+			arg: order{
+				Status: "shipped",
+			},
+			opts: []Option{WithContext(admin)},
+			want: want{true, 0},
+		},
+		{
+			name: "order with rule errors should still call the validators",
+			arg: order{
+				Status: "shipped",
+				Price:  priceRange{Min: 1, Max: 1000},
+			},
+			want: want{true, 2},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+
+	t.Run("errors should be reported under the struct's path", func(t *testing.T) {
+		res, _ := ValidateStruct(order{Status: "shipped", Price: priceRange{Max: 1000}})
+
+		fields := map[string]bool{}
+
+		for _, err := range res.Errors {
+			fieldErr, ok := err.(*FieldError)
+			if !ok {
+				t.Fatalf("expected a *FieldError but got %T", err)
+			}
+
+			fields[fieldErr.Field] = true
+		}
+
+		if !fields[""] || !fields["Price.max"] {
+			t.Errorf("expected errors on the order and Price.max but got %v", fields)
+		}
+	})
+}
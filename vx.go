@@ -1,15 +1,70 @@
 package vx
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"reflect"
 	"strings"
 	"vx/internal"
 )
 
+// Wrapped by the error we give when a number with a fractional part is given
+// to a field which expects an integer. Check for it with `errors.Is`.
+var ErrNotWholeNumber = internal.ErrNotWholeNumber
+
+// An error found during the validation along with where it was found. Errors
+// about a struct as a whole, like a `FieldGroup`, have the name of the struct
+// in `Field`, which is "" for the root struct.
+//
+// Every error of the validation itself is a `*FieldError`, with the key of the
+// rule in the tag as `Code`, or "type" for a value of the wrong type. Internal
+// errors, the ones with `ok` as false, are not.
+//
+// In JSON, a `FieldError` is an object with:
+//   - "field": the `Field`, like "filter.limt"
+//   - "path": the `Path` as an array, a string for a key and a number for an
+//     index, like ["items", 1, "sku"]
+//   - "code": the `Code`, like "minLength"
+//   - "message": what `Error` gives
+//   - "params": the `Params`, left out when there are none
+//   - "position": the `Position` with "offset", "line" and "column", left out
+//     when it isn't set
+//
+// Ex:
+//
+//	{
+//		"field": "filter.limt",
+//		"path": ["filter", "limt"],
+//		"code": "unknownField",
+//		"message": "filter.limt is not a known field, did you mean limit?",
+//		"params": {"suggestion": "limit"},
+//		"position": {"offset": 45, "line": 3, "column": 27}
+//	}
+//
+// Only the message of `Err` comes back from the JSON, as a new error, and the
+// numbers in "params" come back as float64.
+type FieldError = internal.FieldError
+
+// Where something is in a JSON source, see `Unmarshal`.
+type Position = internal.Position
+
+// Where a field is from the root of what was validated, see `FieldError.Path`.
+//
+// Ex:
+// path.JSONPointer() -> "/complexA/simple_a/3"
+// path.String() -> "complexA.simple_a[3]"
+type Path = internal.Path
+
+// A key of an object or an index of an array in a `Path`.
+type PathSegment = internal.PathSegment
+
 type VxResult struct {
 	Errors []error
+	// Whether the validation stopped before doing all the checks because of
+	// `MaxErrors` or `FirstErrorPerField`, so there might be more errors than
+	// the ones in `Errors`.
+	Truncated bool
 }
 
 func (v VxResult) String() string {
@@ -23,6 +78,89 @@ func (v VxResult) String() string {
 	return sb.String()
 }
 
+// How a `VxResult` looks in JSON, see `VxResult.MarshalJSON`.
+type resultJSON struct {
+	Errors    []json.RawMessage `json:"errors"`
+	Truncated bool              `json:"truncated"`
+}
+
+// Errors which are not `*FieldError`s in JSON.
+type errorJSON struct {
+	Message string `json:"message"`
+}
+
+// Gives the result as a JSON object with:
+//   - "errors": an array, always there even when empty, with every
+//     `*FieldError` as described in `FieldError` and any other error, like an
+//     internal one, as an object with only the "message"
+//   - "truncated": the `Truncated`
+//
+// Ex:
+//
+//	{
+//		"errors": [
+//			{"field": "age", "path": ["age"], "code": "required", "message": "age is required"},
+//			{"message": "selected an unknown field nme"}
+//		],
+//		"truncated": false
+//	}
+func (v VxResult) MarshalJSON() ([]byte, error) {
+	j := resultJSON{
+		Errors:    []json.RawMessage{},
+		Truncated: v.Truncated,
+	}
+
+	for _, err := range v.Errors {
+		var data []byte
+		var marshalErr error
+
+		if fieldErr, ok := err.(*FieldError); ok {
+			data, marshalErr = json.Marshal(fieldErr)
+		} else {
+			data, marshalErr = json.Marshal(errorJSON{Message: err.Error()})
+		}
+
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+
+		j.Errors = append(j.Errors, data)
+	}
+
+	return json.Marshal(j)
+}
+
+// Brings back a `VxResult` from the JSON of `MarshalJSON`. An error with a
+// "code" comes back as a `*FieldError`, any other as a plain error with the
+// same message.
+func (v *VxResult) UnmarshalJSON(data []byte) error {
+	var j resultJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*v = VxResult{
+		Errors:    []error{},
+		Truncated: j.Truncated,
+	}
+
+	for _, raw := range j.Errors {
+		var fieldErr FieldError
+		if err := json.Unmarshal(raw, &fieldErr); err != nil {
+			return err
+		}
+
+		if fieldErr.Code == "" {
+			v.Errors = append(v.Errors, fieldErr.Err)
+			continue
+		}
+
+		v.Errors = append(v.Errors, &fieldErr)
+	}
+
+	return nil
+}
+
 func (v VxResult) StringArray() []string {
 	var errArray []string = []string{}
 
@@ -33,6 +171,91 @@ func (v VxResult) StringArray() []string {
 	return errArray
 }
 
+// Whether there is any error in the result.
+func (v VxResult) HasErrors() bool {
+	return len(v.Errors) > 0
+}
+
+// Gives the result as a plain error, nil if there are no errors. The error is
+// made with `errors.Join`, so `errors.Is` and `errors.As` look at each of the
+// errors in it.
+//
+// Ex:
+//
+//	if err := res.Err(); err != nil {
+//		var fieldErr *vx.FieldError
+//		if errors.As(err, &fieldErr) { ... }
+//	}
+func (v VxResult) Err() error {
+	return errors.Join(v.Errors...)
+}
+
+// Gives the `*FieldError`s by the names of their fields, like "name" or
+// "ComplexA.simple_a". The errors about the root struct are under "".
+// Internal errors aren't in it, they are not about any field.
+func (v VxResult) ByField() map[string][]*FieldError {
+	byField := map[string][]*FieldError{}
+
+	for _, err := range v.Errors {
+		if fieldErr, ok := err.(*FieldError); ok {
+			byField[fieldErr.Field] = append(byField[fieldErr.Field], fieldErr)
+		}
+	}
+
+	return byField
+}
+
+// Gives the result with only the `*FieldError`s of the rules with the given
+// codes, like "required" or "type".
+func (v VxResult) FilterCode(codes ...string) VxResult {
+	return v.filter(func(fieldErr *FieldError) bool {
+		for _, code := range codes {
+			if fieldErr.Code == code {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Gives the result with only the `*FieldError`s whose path is `prefix` or is
+// nested in it, like the ones of "items[1].sku" for the `prefix` "items".
+//
+// Ex:
+// res.FilterPath(vx.Path{}.Key("items").Index(1))
+func (v VxResult) FilterPath(prefix Path) VxResult {
+	return v.filter(func(fieldErr *FieldError) bool {
+		if len(fieldErr.Path) < len(prefix) {
+			return false
+		}
+
+		for i, seg := range prefix {
+			if fieldErr.Path[i] != seg {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Gives the result with only the `*FieldError`s that `keep` says so.
+func (v VxResult) filter(keep func(*FieldError) bool) VxResult {
+	filtered := VxResult{
+		Errors:    []error{},
+		Truncated: v.Truncated,
+	}
+
+	for _, err := range v.Errors {
+		if fieldErr, ok := err.(*FieldError); ok && keep(fieldErr) {
+			filtered.Errors = append(filtered.Errors, err)
+		}
+	}
+
+	return filtered
+}
+
 // Parses and validates all the field's values of the given struct `v` against
 // the rules mentioned in the "vx" tag.
 //
@@ -41,12 +264,16 @@ func (v VxResult) StringArray() []string {
 // and `ok` represents whether the `res.Errors` were generated due to something
 // else other than the actual validation. Something went wrong before the
 // validation, like the parsing of the struct or making `Tag`.
-func ValidateStruct(v any) (res VxResult, ok bool) {
+//
+// The behaviour can be changed with `opts`, see `Option`.
+func ValidateStruct(v any, opts ...Option) (res VxResult, ok bool) {
 	ok = true
 	res = VxResult{
 		Errors: []error{},
 	}
 
+	o := makeOptions(opts)
+
 	parsedStruct, err := internal.ParseStruct(v)
 	if err != nil {
 		res.Errors = append(res.Errors, err)
@@ -56,6 +283,51 @@ func ValidateStruct(v any) (res VxResult, ok bool) {
 		return res, ok
 	}
 
+	if o.writeBack && reflect.ValueOf(v).Kind() != reflect.Ptr {
+		ok = false
+		res.Errors = append(res.Errors, fmt.Errorf("write back: expected a pointer to struct, received %s", reflect.ValueOf(v).Kind()))
+	}
+
+	return validateParsed(parsedStruct, o, res, ok)
+}
+
+// Validates a single value `v` against the rules in `tag`, the same way as
+// a field of a struct with the tag `vx:"<tag>"` would be. Useful for a path
+// param or a header which isn't worth declaring a struct for.
+//
+// Ex:
+// res, ok := vx.ValidateValue(r.URL.Query().Get("id"), "name=id, required, minLength=8")
+//
+// The errors are about a field named after "name=" in the tag, "value"
+// otherwise. There are no other fields, so the cross field rules like
+// "eqField=" are reported as an internal error. `WithWriteBack` has nothing
+// to write back to, use `ValidateStruct` for the converted values. Same goes
+// for "default=", a missing value is checked as it is.
+func ValidateValue(v any, tag string, opts ...Option) (res VxResult, ok bool) {
+	res = VxResult{
+		Errors: []error{},
+	}
+
+	parsed := internal.ParseValue(v, tag)
+	field := parsed.Fields[0]
+
+	// A broken tag is reported by `validateParsed` along with everything else.
+	if vxTag, err := internal.MakeTag(field); err == nil {
+		for _, rule := range vxTag.Rules {
+			if _, isCross := rule.Rule.(internal.CrossFieldRule); isCross {
+				res.Errors = append(res.Errors, fmt.Errorf("%s: cross field rules can't be used on a single value", field.Name))
+				return res, false
+			}
+		}
+	}
+
+	return validateParsed(parsed, makeOptions(opts), res, true)
+}
+
+// Makes the tags of the already parsed struct and validates it, the part that
+// is shared by `ValidateStruct` and `ValidateValue`. `res` and `ok` have what
+// was found before parsing it.
+func validateParsed(parsedStruct internal.VxStruct, o options, res VxResult, ok bool) (VxResult, bool) {
 	// FIXME: Making these maps might not be the best way to do this.
 	fieldMap := map[string]internal.VxField{}
 	tagMap := map[string]internal.VxTag{}
@@ -73,175 +345,697 @@ func ValidateStruct(v any) (res VxResult, ok bool) {
 		fieldMap[field.Name] = field
 	}
 
+	// The fields referred by the cross field rules must exist, checking it
+	// here so that a typo is reported every time and not only when the
+	// rule happens to be executed.
+	for _, field := range parsedStruct.Fields {
+		for _, rule := range tagMap[field.Name].Rules {
+			if cr, isCross := rule.Rule.(internal.CrossFieldRule); isCross {
+				if _, err := resolveRefs(cr, field.Name, fieldMap); err != nil {
+					ok = false
+					res.Errors = append(res.Errors, err)
+				}
+			}
+		}
+	}
+
+	groupMap := map[string][]internal.FieldGroup{}
+
+	for _, info := range parsedStruct.Structs {
+		groups, err := makeFieldGroups(info)
+		if err != nil {
+			ok = false
+			res.Errors = append(res.Errors, err)
+		}
+
+		for _, group := range groups {
+			if _, err := resolveFieldGroup(info.Name, group, fieldMap); err != nil {
+				ok = false
+				res.Errors = append(res.Errors, err)
+			}
+		}
+
+		groupMap[info.Name] = groups
+	}
+
+	for _, err := range checkGroups(o.groups, parsedStruct, tagMap, groupMap) {
+		ok = false
+		res.Errors = append(res.Errors, err)
+	}
+
+	for _, name := range append(append([]string{}, o.only...), o.except...) {
+		if !hasField(name, fieldMap) {
+			ok = false
+			res.Errors = append(res.Errors, fmt.Errorf("selected an unknown field %s", name))
+		}
+	}
+
 	// We have an internal error, so we are not going to return them without
 	// executing rules on the parsedStruct's `Fields`.
 	if !ok {
 		return res, ok
 	}
 
-	// Validate the type of the value. Return an error if the type is wrong.
+	val := validation{
+		o:         o,
+		parsed:    parsedStruct,
+		fieldMap:  fieldMap,
+		tagMap:    tagMap,
+		groupMap:  groupMap,
+		paths:     map[string]internal.Path{},
+		labels:    map[string]string{},
+		errors:    []error{},
+		failed:    map[string]bool{},
+		wrongType: map[string]bool{},
+	}
+
 	for _, field := range parsedStruct.Fields {
+		val.paths[field.Name] = field.Path
+	}
+
+	for _, info := range parsedStruct.Structs {
+		val.paths[info.Name] = info.Path
+	}
+
+	val.run()
+
+	res.Errors = append(res.Errors, val.errors...)
+	res.Truncated = val.truncated
+
+	// fmt.Println("\nParsedStruct:", parsedStruct)
+	// fmt.Println("\nResult:", ok, res)
+
+	return res, ok
+}
+
+// Validation of a parsed struct whose tags were all made without any errors.
+type validation struct {
+	o        options
+	parsed   internal.VxStruct
+	fieldMap map[string]internal.VxField
+	tagMap   map[string]internal.VxTag
+	groupMap map[string][]internal.FieldGroup
+	// Paths of the fields and structs by their names.
+	paths map[string]internal.Path
+	// Labels of the fields by their names, see `label`.
+	labels map[string]string
+
+	errors []error
+	// Names of the fields and structs that have an error.
+	failed map[string]bool
+	// Names of the fields whose value is not of the type in their tag.
+	wrongType map[string]bool
+	// Whether the validation stopped before doing all the checks, because
+	// of `MaxErrors` or `FirstErrorPerField`.
+	truncated bool
+}
+
+func (v *validation) run() {
+	v.checkFields()
+	v.execRules()
+	v.execStructs()
+}
+
+// Whether the validation should stop because of `MaxErrors`.
+func (v *validation) stop() bool {
+	return v.o.maxErrors > 0 && len(v.errors) >= v.o.maxErrors
+}
+
+// Whether the field or struct with the `name` should not be checked anymore
+// because of `FirstErrorPerField`.
+func (v *validation) skip(name string) bool {
+	return v.o.firstErrorPerField && v.failed[name]
+}
+
+// Whether a check that is about to be done, or an error that is about to be
+// reported, for the field or struct with the `name` should be dropped because
+// of `stop` or `skip`. Dropping it means the result is truncated, so this is
+// only asked when there is something to drop.
+func (v *validation) drop(name string) bool {
+	if v.stop() || v.skip(name) {
+		v.truncated = true
+		return true
+	}
+
+	return false
+}
+
+// Adds the errors of the field or struct with the `name`, as long as the
+// limits set by the options allow it. The errors are reported as
+// `*FieldError`s with the `code`, unless they already are one.
+func (v *validation) report(name string, code string, errs ...error) {
+	for _, err := range errs {
+		if v.drop(name) {
+			return
+		}
+
+		fieldErr, isFieldErr := err.(*FieldError)
+		if !isFieldErr {
+			fieldErr = &FieldError{Field: name, Path: v.paths[name], Code: code, Err: err}
+
+			var ruleErr *internal.RuleError
+			if errors.As(err, &ruleErr) {
+				fieldErr.Err = ruleErr.Err
+				fieldErr.Params = ruleErr.Params
+			}
+		}
+
+		var value any
+		if field, ok := v.fieldMap[fieldErr.Field]; ok {
+			value = field.Value
+		}
+
+		applyMessage(fieldErr, v.tagMap[fieldErr.Field].Messages, value, v.label, v.o)
+
+		v.errors = append(v.errors, fieldErr)
+		v.failed[name] = true
+	}
+}
+
+// Fills the defaults and validates the types of the values.
+func (v *validation) checkFields() {
+	o := v.o
+
+	for i := range v.parsed.Fields {
+		field := &v.parsed.Fields[i]
+		tag := v.tagMap[field.Name]
+
+		if !o.selected(field.Name) {
+			continue
+		}
+
+		// Fill the missing fields with the "default=" in their tag before
+		// anything else, so that the type check and rules see the default.
+		// Only when the struct was given as a pointer, the rules shouldn't
+		// pass because of a default that the caller never gets.
+		if tag.HasDefault && !field.Present && field.Target.CanSet() {
+			// Can't fail, `MakeTag` has already made it once successfully.
+			value, _ := tag.MakeDefault()
+
+			field.Set(value)
+			field.Value = value
+			field.ValueType = reflect.TypeOf(value)
+			field.Present = true
+			v.fieldMap[field.Name] = *field
+		}
+
 		// `field.ValueType.Kind()` panics when `field.Value` is `nil` !!!
-		if field.Value != nil {
-			tag := tagMap[field.Name]
+		if field.Value != nil && tag.HasExplicitType && !v.drop(field.Name) {
+			converted, errs := checkType(field, tag, o, v.label(field.Name))
+			v.report(field.Name, "type", errs...)
 
-			// Check if the type of the value is valid.
-			if tag.Type != field.ValueType && field.Type.Kind() == reflect.Interface && tag.HasExplicitType && tag.Type.Kind() != reflect.Interface {
-				if tag.Type.Kind() != field.ValueType.Kind() {
-					err = fmt.Errorf("%s should be of type %s but got %s", field.Name, tag.Type, field.ValueType)
-					res.Errors = append(res.Errors, err)
-					// We want to switch over `tag.Type.Kind()` only if it's
-					// same as `field.ValueType.kind()` because then only
-					// it makes sense to compare type of key and/or elem.
+			if len(errs) > 0 {
+				v.wrongType[field.Name] = true
+			}
+
+			if converted {
+				v.fieldMap[field.Name] = *field
+
+				if o.writeBack || o.coerce {
+					field.Set(field.Value)
+				}
+			}
+		}
+	}
+}
+
+// Executes the rules in the tags of the fields, in the order of the fields.
+//
+// The rules of a field whose value is of the wrong type are not executed,
+// they would only fail again for the same reason. Same goes for the cross
+// field rules which refer to such a field.
+func (v *validation) execRules() {
+	o := v.o
+
+	for _, f := range v.parsed.Fields {
+		fieldName := f.Name
+		field := v.fieldMap[fieldName]
+		tag := v.tagMap[fieldName]
+
+		if !o.selected(fieldName) || v.wrongType[fieldName] {
+			continue
+		}
+
+		for _, r := range tag.Rules {
+			if !r.InGroups(o.groups) {
+				continue
+			}
+
+			rule := r.Rule
+
+			if cr, isCross := rule.(internal.CrossFieldRule); isCross {
+				// Can't fail, we have checked all the refs before.
+				refs, _ := resolveRefs(cr, fieldName, v.fieldMap)
+				if !allSelected(refs, o) || v.anyWrongType(refs) {
 					continue
 				}
 
-				switch tag.Type.Kind() {
-				case reflect.Slice:
-					var actualElemType reflect.Type = nil
-					hasElems := false
+				rule = cr.Bind(v.labeled(refs...))
+			}
 
-					mySlice, ok := field.Value.([]any)
-					if ok {
-						for _, elem := range mySlice {
-							hasElems = true
+			if v.drop(fieldName) {
+				break
+			}
 
-							if actualElemType == nil || reflect.TypeOf(elem) != tag.Type.Elem() {
-								actualElemType = reflect.TypeOf(elem)
-							}
-						}
-					}
+			err := rule.Exec(v.labeled(field)[0])
+			if err != nil {
+				v.report(fieldName, r.Code, err)
+
+				if tag.Bail {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Gives the name of the field for the messages, its "label=" or its name,
+// changed by the `LabelResolver` of `WithLabels` if any.
+func (v *validation) label(name string) string {
+	if label, ok := v.labels[name]; ok {
+		return label
+	}
+
+	label := v.tagMap[name].Label
+
+	if v.o.labels != nil {
+		label = v.o.labels(v.paths[name], label)
+	}
+
+	if label == "" {
+		label = name
+	}
+
+	v.labels[name] = label
+	return label
+}
+
+// Gives copies of the `fields` with their labels, for the messages of the
+// rules. Their names stay the same for the params of the errors.
+func (v *validation) labeled(fields ...internal.VxField) []internal.VxField {
+	labeled := make([]internal.VxField, len(fields))
+
+	for i, field := range fields {
+		field.Label = v.label(field.Name)
+		labeled[i] = field
+	}
+
+	return labeled
+}
+
+// Whether any of the `fields` has a value of the wrong type.
+func (v *validation) anyWrongType(fields []internal.VxField) bool {
+	for _, field := range fields {
+		if v.wrongType[field.Name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Executes the field groups and `Validator`s of the structs.
+func (v *validation) execStructs() {
+	o := v.o
+
+	for _, info := range v.parsed.Structs {
+		for _, group := range v.groupMap[info.Name] {
+			if !group.InGroups(o.groups) {
+				continue
+			}
+
+			// Can't fail, we have checked all the groups before.
+			fields, _ := resolveFieldGroup(info.Name, group, v.fieldMap)
+			if !allSelected(fields, o) {
+				continue
+			}
+
+			if v.drop(info.Name) {
+				break
+			}
+
+			if err := group.Exec(v.labeled(fields...)); err != nil {
+				v.report(info.Name, group.Key, &FieldError{
+					Field:  info.Name,
+					Path:   info.Path,
+					Code:   group.Key,
+					Params: map[string]any{"fields": group.Refs},
+					Err:    err,
+				})
+			}
+		}
+
+		if o.selected(info.Name) && hasValidator(info) && !v.drop(info.Name) {
+			v.report(info.Name, "vxValidate", execValidator(info, o)...)
+		}
+	}
+}
+
+// Validates only the fields of `v` with the given names, see `OnlyFields`.
+// Useful for a PATCH request which should only check the fields it has.
+func ValidateFields(v any, names ...string) (res VxResult, ok bool) {
+	return ValidateStruct(v, OnlyFields(names...))
+}
+
+// Validates all the fields of `v` except the ones with the given names, see
+// `ExceptFields`.
+func ValidateExcept(v any, names ...string) (res VxResult, ok bool) {
+	return ValidateStruct(v, ExceptFields(names...))
+}
+
+// Whether all the `fields` are selected by `OnlyFields` and `ExceptFields`.
+func allSelected(fields []internal.VxField, o options) bool {
+	for _, field := range fields {
+		if !o.selected(field.Name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Whether `name` is the name of a field, a nested struct or a key inside of a
+// map field.
+func hasField(name string, fields map[string]internal.VxField) bool {
+	if _, ok := lookupField(name, fields); ok {
+		return true
+	}
+
+	for fieldName := range fields {
+		if strings.HasPrefix(fieldName, name+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checks that the `active` validation groups and the ones in the tags are all
+// registered, so that a typo like "creat" doesn't silently skip all the rules
+// of the "create" group.
+func checkGroups(active []string, parsedStruct internal.VxStruct, tagMap map[string]internal.VxTag, groupMap map[string][]internal.FieldGroup) []error {
+	errs := []error{}
+
+	for _, group := range active {
+		if !isRegisteredGroup(group) {
+			errs = append(errs, fmt.Errorf("selected an unknown validation group %s, see RegisterGroups", group))
+		}
+	}
+
+	for _, field := range parsedStruct.Fields {
+		for _, rule := range tagMap[field.Name].Rules {
+			for _, group := range rule.Groups {
+				if !isRegisteredGroup(group) {
+					errs = append(errs, fmt.Errorf("%s: %s is in an unknown validation group %s, see RegisterGroups", field.Name, rule.Code, group))
+				}
+			}
+		}
+	}
+
+	for _, info := range parsedStruct.Structs {
+		for _, fieldGroup := range groupMap[info.Name] {
+			for _, group := range fieldGroup.Groups {
+				if !isRegisteredGroup(group) {
+					errs = append(errs, fmt.Errorf("%s: %s is in an unknown validation group %s, see RegisterGroups", info.Type, fieldGroup.Key, group))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// Finds the fields that a cross field rule on the field `from` refers to.
+//
+// A ref is looked up among the siblings of `from` first, so that a rule in a
+// nested struct can simply say "ltField=to". Otherwise it's taken as the full
+// name of the field, like "Range.to".
+func resolveRefs(cr internal.CrossFieldRule, from string, fields map[string]internal.VxField) ([]internal.VxField, error) {
+	refs := []internal.VxField{}
+
+	for _, ref := range cr.Refs() {
+		if i := strings.LastIndex(from, "."); i != -1 {
+			if field, ok := lookupField(from[:i+1]+ref, fields); ok {
+				refs = append(refs, field)
+				continue
+			}
+		}
+
+		field, ok := lookupField(ref, fields)
+		if !ok {
+			return nil, fmt.Errorf("%s: refers to an unknown field %s", from, ref)
+		}
+
+		refs = append(refs, field)
+	}
+
+	return refs, nil
+}
+
+// Finds the field with the full `name`, which can also point inside of a map
+// field, like "filter.kind" for the key "kind" of the map in "filter".
+//
+// Keys of a map can't be known before there is a value, so a missing key just
+// gives a field with a nil value as long as the map field itself exists.
+func lookupField(name string, fields map[string]internal.VxField) (internal.VxField, bool) {
+	if field, ok := fields[name]; ok {
+		return field, true
+	}
+
+	parts := strings.Split(name, ".")
+
+	for i := len(parts) - 1; i > 0; i-- {
+		parent, ok := fields[strings.Join(parts[:i], ".")]
+		if !ok {
+			continue
+		}
+
+		if kind := parent.Type.Kind(); kind != reflect.Map && kind != reflect.Interface {
+			return internal.VxField{}, false
+		}
+
+		value := parent.Value
+
+		for _, key := range parts[i:] {
+			m := reflect.ValueOf(value)
+			if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+				value = nil
+				break
+			}
+
+			elem := m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+			if !elem.IsValid() {
+				value = nil
+				break
+			}
+
+			value = elem.Interface()
+		}
+
+		// Values in a map are just like the values of an `any` field.
+		return internal.VxField{
+			Name:      name,
+			Type:      reflect.TypeOf((*any)(nil)).Elem(),
+			Value:     value,
+			ValueType: reflect.TypeOf(value),
+			Present:   value != nil,
+		}, true
+	}
+
+	return internal.VxField{}, false
+}
+
+// Checks the type of the non nil `field.Value` against the type in the `tag`
+// and returns the errors for all the mismatches.
+//
+// JSON numbers (and strings with `WithCoercion`) are converted to the type in
+// the tag, in which case `field` is updated with the converted value and
+// `converted` is true.
+func checkType(field *internal.VxField, tag internal.VxTag, o options, label string) (converted bool, errs []error) {
+	// Check if the type of the value is valid.
+	if tag.Type != field.ValueType && field.Type.Kind() == reflect.Interface && tag.HasExplicitType && tag.Type.Kind() != reflect.Interface {
+		value, ok, err := internal.ConvertNumber(label, field.Value, tag.Type, o.typeNamer())
+		if !ok && o.coerce {
+			value, ok, err = internal.CoerceString(label, field.Value, tag.Type, o.typeNamer())
+		}
+
+		expected, actual := o.typeNames(tag.Type, field.ValueType)
+
+		if err != nil {
+			return false, []error{typeError(err, expected, actual)}
+		}
+
+		if ok {
+			field.Value = value
+			field.ValueType = reflect.TypeOf(value)
+			return true, nil
+		}
 
-					if tag.Type.Elem().Kind() != field.ValueType.Elem().Kind() {
-						if tag.Type.Elem().Kind() != reflect.Interface && field.ValueType.Elem().Kind() != reflect.Interface || (hasElems && tag.Type.Elem() != actualElemType) {
-							elemType := field.ValueType.Elem()
+		if tag.Type.Kind() != field.ValueType.Kind() {
+			errs = append(errs, typeError(fmt.Errorf("%s should be of type %s but got %s", label, expected, actual), expected, actual))
+			// We want to switch over `tag.Type.Kind()` only if it's
+			// same as `field.ValueType.kind()` because then only
+			// it makes sense to compare type of key and/or elem.
+			return false, errs
+		}
+
+		switch tag.Type.Kind() {
+		case reflect.Slice:
+			var actualElemType reflect.Type = nil
+			hasElems := false
 
-							if hasElems {
-								elemType = actualElemType
-							}
+			mySlice, ok := field.Value.([]any)
+			if ok {
+				for _, elem := range mySlice {
+					hasElems = true
 
-							err = fmt.Errorf("%s should be an array of elem of type %s but got %s", field.Name, tag.Type.Elem(), elemType)
-							res.Errors = append(res.Errors, err)
-						}
+					if actualElemType == nil || reflect.TypeOf(elem) != tag.Type.Elem() {
+						actualElemType = reflect.TypeOf(elem)
 					}
-				case reflect.Array:
-					var actualElemType reflect.Type = nil
-					hasElems := false
-
-					mySlice, ok := field.Value.([]any)
-					if ok {
-						for _, elem := range mySlice {
-							hasElems = true
-
-							if actualElemType == nil || reflect.TypeOf(elem) != tag.Type.Elem() {
-								actualElemType = reflect.TypeOf(elem)
-							}
-						}
+				}
+			}
+
+			if tag.Type.Elem().Kind() != field.ValueType.Elem().Kind() {
+				if tag.Type.Elem().Kind() != reflect.Interface && field.ValueType.Elem().Kind() != reflect.Interface || (hasElems && tag.Type.Elem() != actualElemType) {
+					elemType := field.ValueType.Elem()
+
+					if hasElems {
+						elemType = actualElemType
 					}
 
-					if tag.Type.Elem().Kind() != field.ValueType.Elem().Kind() {
-						if tag.Type.Elem().Kind() != reflect.Interface && field.ValueType.Elem().Kind() != reflect.Interface || (hasElems && tag.Type.Elem() != actualElemType) {
-							elemType := field.ValueType.Elem()
+					expectedElem, actualElem := o.typeNames(tag.Type.Elem(), elemType)
+					errs = append(errs, typeError(fmt.Errorf("%s should be an array of elem of type %s but got %s", label, expectedElem, actualElem), expectedElem, actualElem))
+				}
+			}
+		case reflect.Array:
+			var actualElemType reflect.Type = nil
+			hasElems := false
 
-							if hasElems {
-								elemType = actualElemType
-							}
+			mySlice, ok := field.Value.([]any)
+			if ok {
+				for _, elem := range mySlice {
+					hasElems = true
 
-							err = fmt.Errorf("%s should be an array of elem of type %s but got %s", field.Name, tag.Type.Elem(), elemType)
-							res.Errors = append(res.Errors, err)
-						}
+					if actualElemType == nil || reflect.TypeOf(elem) != tag.Type.Elem() {
+						actualElemType = reflect.TypeOf(elem)
 					}
+				}
+			}
 
-					if tag.Type.Len() != field.ValueType.Len() {
-						err = fmt.Errorf("%s should be an array of length %d but got %d", field.Name, tag.Type.Len(), field.ValueType.Len())
-						res.Errors = append(res.Errors, err)
-					}
-				case reflect.Map:
-					var actualKeyType, actualElemType reflect.Type = nil, nil
-					hasElems := false
-
-					myMap, ok := field.Value.(map[string]any)
-					if ok {
-						for key, elem := range myMap {
-							hasElems = true
-
-							// If `key` if of type `any` in `Field.Value` then `ValueType.Key()`
-							// can be of more than 1 type. We want `actualKeyType` to change
-							// only when it's nil (first time) and when the reflect.TypeOf(key)
-							// doens't match to the key type from the tag.
-							//
-							// One thing to note is that the error message will show `actualKeyType`
-							// which will be the last wrong type key/elem that we found.
-							//
-							// Same goes for `actualElemType`.
-							if actualKeyType == nil || reflect.TypeOf(key) != tag.Type.Key() {
-								actualKeyType = reflect.TypeOf(key)
-							}
-
-							if actualElemType == nil || reflect.TypeOf(elem) != tag.Type.Elem() {
-								actualElemType = reflect.TypeOf(elem)
-							}
-						}
+			if tag.Type.Elem().Kind() != field.ValueType.Elem().Kind() {
+				if tag.Type.Elem().Kind() != reflect.Interface && field.ValueType.Elem().Kind() != reflect.Interface || (hasElems && tag.Type.Elem() != actualElemType) {
+					elemType := field.ValueType.Elem()
+
+					if hasElems {
+						elemType = actualElemType
 					}
 
-					if tag.Type.Key().Kind() != field.ValueType.Key().Kind() {
-						if tag.Type.Key().Kind() != reflect.Interface && field.ValueType.Key().Kind() != reflect.Interface || (hasElems && tag.Type.Key() != actualKeyType) {
-							keyType := field.ValueType.Key()
+					expectedElem, actualElem := o.typeNames(tag.Type.Elem(), elemType)
+					errs = append(errs, typeError(fmt.Errorf("%s should be an array of elem of type %s but got %s", label, expectedElem, actualElem), expectedElem, actualElem))
+				}
+			}
+
+			if tag.Type.Len() != field.ValueType.Len() {
+				errs = append(errs, typeError(fmt.Errorf("%s should be an array of length %d but got %d", label, tag.Type.Len(), field.ValueType.Len()), expected, actual))
+			}
+		case reflect.Map:
+			var actualKeyType, actualElemType reflect.Type = nil, nil
+			hasElems := false
 
-							if hasElems {
-								keyType = actualKeyType
-							}
+			myMap, ok := field.Value.(map[string]any)
+			if ok {
+				for key, elem := range myMap {
+					hasElems = true
 
-							err = fmt.Errorf("%s should be a map with key of type %s and elem of type %s but got map with key of type %s", field.Name, tag.Type.Key(), tag.Type.Elem(), keyType)
-							res.Errors = append(res.Errors, err)
-						}
+					// If `key` if of type `any` in `Field.Value` then `ValueType.Key()`
+					// can be of more than 1 type. We want `actualKeyType` to change
+					// only when it's nil (first time) and when the reflect.TypeOf(key)
+					// doens't match to the key type from the tag.
+					//
+					// One thing to note is that the error message will show `actualKeyType`
+					// which will be the last wrong type key/elem that we found.
+					//
+					// Same goes for `actualElemType`.
+					if actualKeyType == nil || reflect.TypeOf(key) != tag.Type.Key() {
+						actualKeyType = reflect.TypeOf(key)
 					}
 
-					if tag.Type.Elem().Kind() != field.ValueType.Elem().Kind() {
-						if tag.Type.Elem().Kind() != reflect.Interface && field.ValueType.Elem().Kind() != reflect.Interface || (hasElems && tag.Type.Elem() != actualElemType) {
-							elemType := field.ValueType.Elem()
+					if actualElemType == nil || reflect.TypeOf(elem) != tag.Type.Elem() {
+						actualElemType = reflect.TypeOf(elem)
+					}
+				}
+			}
 
-							if hasElems {
-								elemType = actualElemType
-							}
+			if tag.Type.Key().Kind() != field.ValueType.Key().Kind() {
+				if tag.Type.Key().Kind() != reflect.Interface && field.ValueType.Key().Kind() != reflect.Interface || (hasElems && tag.Type.Key() != actualKeyType) {
+					keyType := field.ValueType.Key()
 
-							err = fmt.Errorf("%s should be a map with key of type %s and elem of type %s but got map with elem of type %s", field.Name, tag.Type.Key(), tag.Type.Elem(), elemType)
-							res.Errors = append(res.Errors, err)
-						}
+					if hasElems {
+						keyType = actualKeyType
+					}
+
+					expectedKey, actualKey := o.typeNames(tag.Type.Key(), keyType)
+
+					if o.jsonTypes {
+						errs = append(errs, typeError(fmt.Errorf("%s should be an object with keys of type %s but got keys of type %s", label, expectedKey, actualKey), expectedKey, actualKey))
+					} else {
+						errs = append(errs, typeError(fmt.Errorf("%s should be a map with key of type %s and elem of type %s but got map with key of type %s", label, tag.Type.Key(), tag.Type.Elem(), keyType), expectedKey, actualKey))
 					}
-				default:
-					err = fmt.Errorf("%s should be of type %s but got %s", field.Name, tag.Type, field.ValueType)
-					res.Errors = append(res.Errors, err)
 				}
 			}
-		}
 
-		// This is a bit tricky. Although we have a "required" Rule to check and warn
-		// the user if a required value is not present or "empty" but if the developer
-		// forgets to add the "required" Rule and then reads this field, that would
-		// lead to a panic during runtime, which is NOT SO GOOD !!
-		//
-		// So in order to prevent the runtime panics for Vx users, we should set the
-		// field.Value to be a default of the type that was being expected. If the
-		// expected type is any, we will default it to empty string.
-		if field.Value == nil {
-			log.Printf("YIKES! This is bad, %s is nil which can be a nasty runtime error.", field.Name)
+			if tag.Type.Elem().Kind() != field.ValueType.Elem().Kind() {
+				if tag.Type.Elem().Kind() != reflect.Interface && field.ValueType.Elem().Kind() != reflect.Interface || (hasElems && tag.Type.Elem() != actualElemType) {
+					elemType := field.ValueType.Elem()
+
+					if hasElems {
+						elemType = actualElemType
+					}
+
+					expectedElem, actualElem := o.typeNames(tag.Type.Elem(), elemType)
+
+					if o.jsonTypes {
+						errs = append(errs, typeError(fmt.Errorf("%s should be an object with values of type %s but got values of type %s", label, expectedElem, actualElem), expectedElem, actualElem))
+					} else {
+						errs = append(errs, typeError(fmt.Errorf("%s should be a map with key of type %s and elem of type %s but got map with elem of type %s", label, tag.Type.Key(), tag.Type.Elem(), elemType), expectedElem, actualElem))
+					}
+				}
+			}
+		default:
+			errs = append(errs, typeError(fmt.Errorf("%s should be of type %s but got %s", label, expected, actual), expected, actual))
 		}
 	}
 
-	for fieldName, tag := range tagMap {
-		field := fieldMap[fieldName]
+	return false, errs
+}
 
-		for _, rule := range tag.Rules {
-			err := rule.Exec(field)
-			if err != nil {
-				res.Errors = append(res.Errors, err)
-			}
+// Gives the type error `err` with the names of the types that its message is
+// about as params, like the ones of the elems for an array.
+func typeError(err error, expected string, actual string) error {
+	return internal.WithParams(err, map[string]any{"expected": expected, "actual": actual})
+}
+
+// Gives the names of the `expected` and `actual` types for the messages, the
+// Go ones or the JSON Schema ones with `WithJSONTypeNames`. The Go names are
+// used when the JSON Schema ones are the same, like for int and int8, as the
+// message wouldn't make sense otherwise.
+func (o options) typeNames(expected reflect.Type, actual reflect.Type) (string, string) {
+	if o.jsonTypes {
+		e, a := internal.JSONTypeName(expected), internal.JSONTypeName(actual)
+		if e != a {
+			return e, a
 		}
 	}
 
-	// fmt.Println("\nParsedStruct:", parsedStruct)
-	// fmt.Println("\nResult:", ok, res)
+	return internal.GoTypeName(expected), internal.GoTypeName(actual)
+}
 
-	return res, ok
+// Gives the names of the types for the messages of the conversions, where
+// there is only the expected type to name.
+func (o options) typeNamer() internal.TypeNamer {
+	if o.jsonTypes {
+		return internal.JSONTypeName
+	}
+
+	return internal.GoTypeName
 }
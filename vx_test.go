@@ -1,6 +1,9 @@
 package vx
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -12,13 +15,14 @@ type want struct {
 type validateStructTest struct {
 	name string
 	arg  any
+	opts []Option
 	want want
 }
 
 func runValidateStructTests(tests []validateStructTest, t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			res, ok := ValidateStruct(test.arg)
+			res, ok := ValidateStruct(test.arg, test.opts...)
 
 			if ok != test.want.ok {
 				t.Error(res.String())
@@ -253,6 +257,32 @@ func TestRequired(t *testing.T) {
 		A any `vx:"required"`
 	}
 
+	type aAnyAllowZero struct {
+		A any `vx:"required(allowZero)"`
+	}
+
+	type aAnyNonZero struct {
+		A any `vx:"required(nonZero)"`
+	}
+
+	type aStruct struct {
+		A struct{ B string } `vx:"required"`
+	}
+
+	type aPointer struct {
+		A *int `vx:"required"`
+	}
+
+	type aNotBlank struct {
+		A any `vx:"notBlank"`
+	}
+
+	type invalidOption struct {
+		A any `vx:"required(allowEmpty)"`
+	}
+
+	zero := 0
+
 	tests := []validateStructTest{
 		{
 			name: "aAny with bool value should not give an error",
@@ -261,6 +291,13 @@ func TestRequired(t *testing.T) {
 			},
 			want: want{true, 0},
 		},
+		{
+			name: "aAny with true value should not give an error",
+			arg: aAny{
+				A: true,
+			},
+			want: want{true, 0},
+		},
 		{
 			name: "aAny with int value should not give an error",
 			arg: aAny{
@@ -275,6 +312,13 @@ func TestRequired(t *testing.T) {
 			},
 			want: want{true, 0},
 		},
+		{
+			name: "aAny with non zero int value should not give an error",
+			arg: aAny{
+				A: 1,
+			},
+			want: want{true, 0},
+		},
 		{
 			name: "aAny with non-empty string value should not give an error",
 			arg: aAny{
@@ -283,17 +327,45 @@ func TestRequired(t *testing.T) {
 			want: want{true, 0},
 		},
 		{
-			name: "aAny with slice value should not give an error",
+			name: "aAny with empty string value should give an error",
+			arg: aAny{
+				A: "",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAny with whitespace only string value should give an error",
+			arg: aAny{
+				A: " \t\n",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAny with empty slice value should give an error",
 			arg: aAny{
 				A: []any{},
 			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAny with non-empty slice value should not give an error",
+			arg: aAny{
+				A: []any{1},
+			},
 			want: want{true, 0},
 		},
 		{
-			name: "aAny with map value should not give an error",
+			name: "aAny with empty map value should give an error",
 			arg: aAny{
 				A: map[any]any{},
 			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAny with non-empty map value should not give an error",
+			arg: aAny{
+				A: map[any]any{"a": 1},
+			},
 			want: want{true, 0},
 		},
 		{
@@ -301,9 +373,1256 @@ func TestRequired(t *testing.T) {
 			arg:  aAny{},
 			want: want{true, 1},
 		},
+		{
+			name: "aAnyAllowZero with false value should not give an error",
+			arg: aAnyAllowZero{
+				A: false,
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "aAnyAllowZero with 0 int value should not give an error",
+			arg: aAnyAllowZero{
+				A: 0,
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "aAnyAllowZero with empty string value should give an error",
+			arg: aAnyAllowZero{
+				A: "",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAnyNonZero with false value should give an error",
+			arg: aAnyNonZero{
+				A: false,
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAnyNonZero with 0 int value should give an error",
+			arg: aAnyNonZero{
+				A: 0,
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAnyNonZero with 0.0 float64 value should give an error",
+			arg: aAnyNonZero{
+				A: 0.0,
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "aAnyNonZero with non zero int value should not give an error",
+			arg: aAnyNonZero{
+				A: 1,
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "aStruct with zero value should give an error",
+			arg:  aStruct{},
+			want: want{true, 1},
+		},
+		{
+			name: "aStruct with non zero value should not give an error",
+			arg: aStruct{
+				A: struct{ B string }{"b"},
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "aPointer with nil value should give an error",
+			arg:  aPointer{},
+			want: want{true, 1},
+		},
+		{
+			name: "aPointer pointing to 0 should not give an error",
+			arg: aPointer{
+				A: &zero,
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "aNotBlank with nil value should not give an error",
+			arg:  aNotBlank{},
+			want: want{true, 0},
+		},
+		{
+			name: "aNotBlank with whitespace only string value should give an error",
+			arg: aNotBlank{
+				A: "  ",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "aNotBlank with int value should give an error",
+			arg: aNotBlank{
+				A: 1,
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "invalidOption should give an internal error",
+			arg:  invalidOption{},
+			want: want{false, 1},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+}
+
+func TestJSONNumbers(t *testing.T) {
+	type anyToInt struct {
+		A any `vx:"type=int"`
+	}
+
+	type anyToFloat64 struct {
+		A any `vx:"type=float64"`
+	}
+
+	type anyToIntSlice struct {
+		A any `vx:"type=[]int"`
+	}
+
+	type anyToStringToIntMap struct {
+		A any `vx:"type=map[string]int"`
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "anyToInt with whole float64 value should not give an error",
+			arg: anyToInt{
+				A: float64(42),
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "anyToInt with fractional float64 value should give an error",
+			arg: anyToInt{
+				A: 2.5,
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "anyToInt with whole json.Number value should not give an error",
+			arg: anyToInt{
+				A: json.Number("42"),
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "anyToInt with fractional json.Number value should give an error",
+			arg: anyToInt{
+				A: json.Number("2.5"),
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "anyToFloat64 with json.Number value should not give an error",
+			arg: anyToFloat64{
+				A: json.Number("2.5"),
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "anyToIntSlice with whole float64 elems should not give an error",
+			arg: anyToIntSlice{
+				A: []any{float64(1), float64(2)},
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "anyToIntSlice with a fractional float64 elem should give an error",
+			arg: anyToIntSlice{
+				A: []any{float64(1), 2.5},
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "anyToStringToIntMap with whole float64 elems should not give an error",
+			arg: anyToStringToIntMap{
+				A: map[string]any{"a": float64(1)},
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "write back without a pointer should give an internal error",
+			arg: anyToInt{
+				A: float64(42),
+			},
+			opts: []Option{WithWriteBack()},
+			want: want{false, 1},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+
+	t.Run("fractional number error should wrap ErrNotWholeNumber", func(t *testing.T) {
+		res, _ := ValidateStruct(anyToInt{A: 2.5})
+
+		if len(res.Errors) != 1 || !errors.Is(res.Errors[0], ErrNotWholeNumber) {
+			t.Errorf("expected an error wrapping ErrNotWholeNumber but got %v", res.Errors)
+		}
+	})
+
+	t.Run("write back should assign the converted values", func(t *testing.T) {
+		a := anyToInt{A: float64(42)}
+		s := anyToIntSlice{A: []any{float64(1), float64(2)}}
+
+		ValidateStruct(&a, WithWriteBack())
+		ValidateStruct(&s, WithWriteBack())
+
+		if _, ok := a.A.(int); !ok {
+			t.Errorf("expected A to be an int after write back but got %T", a.A)
+		}
+
+		if _, ok := s.A.([]int); !ok {
+			t.Errorf("expected A to be a []int after write back but got %T", s.A)
+		}
+	})
+
+	t.Run("without write back the field should stay as it is", func(t *testing.T) {
+		a := anyToInt{A: float64(42)}
+
+		ValidateStruct(&a)
+
+		if _, ok := a.A.(float64); !ok {
+			t.Errorf("expected A to still be a float64 but got %T", a.A)
+		}
+	})
+}
+
+func TestCoercion(t *testing.T) {
+	type query struct {
+		Page   any `vx:"name=page, type=int"`
+		Active any `vx:"name=active, type=bool"`
+		Score  any `vx:"name=score, type=float64"`
+		IDs    any `vx:"name=ids, type=[]int"`
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "query with parsable strings should not give an error",
+			arg: query{
+				Page:   "42",
+				Active: "true",
+				Score:  "2.5",
+				IDs:    []string{"1", "2"},
+			},
+			opts: []Option{WithCoercion()},
+			want: want{true, 0},
+		},
+		{
+			name: "query with unparsable strings should give type errors",
+			arg: query{
+				Page:   "forty two",
+				Active: "yes",
+				Score:  "2.5.1",
+				IDs:    []string{"1", "two"},
+			},
+			opts: []Option{WithCoercion()},
+			want: want{true, 4},
+		},
+		{
+			name: "query with a fractional page should give an error",
+			arg: query{
+				Page: "2.5",
+			},
+			opts: []Option{WithCoercion()},
+			want: want{true, 1},
+		},
+		{
+			name: "query with strings should give type errors without coercion",
+			arg: query{
+				Page:   "42",
+				Active: "true",
+				Score:  "2.5",
+			},
+			want: want{true, 3},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+
+	t.Run("coerced values should be assigned back into a pointer", func(t *testing.T) {
+		q := query{Page: "42", Active: "true", Score: "2.5", IDs: []string{"1", "2"}}
+
+		res, _ := ValidateStruct(&q, WithCoercion())
+		if len(res.Errors) != 0 {
+			t.Fatal(res.String())
+		}
+
+		if q.Page != 42 || q.Active != true || q.Score != 2.5 {
+			t.Errorf("expected coerced values but got %#v", q)
+		}
+
+		if ids, ok := q.IDs.([]int); !ok || len(ids) != 2 || ids[1] != 2 {
+			t.Errorf("expected ids to be []int{1, 2} but got %#v", q.IDs)
+		}
+	})
+}
+
+func TestDefault(t *testing.T) {
+	type defaults struct {
+		Int    any               `vx:"type=int, default=10"`
+		Bool   any               `vx:"type=bool, default=true"`
+		String any               `vx:"default=abc"`
+		Slice  any               `vx:"type=[]string, default=[]"`
+		Map    any               `vx:"type=map[string]any, default={}"`
+		Tags   []string          `vx:"default=[\"a\"]"`
+		Meta   map[string]string `vx:"default={}"`
+		Limit  *int              `vx:"default=20"`
+		Ints   []int             `vx:"default=[1, 2], name=ints"`
+		Counts map[string]int    `vx:"default={\"a\": 1, \"b\": 2}"`
+	}
+
+	type requiredWithDefault struct {
+		A any `vx:"type=string, required, default=abc"`
+	}
+
+	type invalidDefault struct {
+		A any `vx:"type=int, default=abc"`
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "requiredWithDefault with nil value should not give an error",
+			arg:  &requiredWithDefault{},
+			want: want{true, 0},
+		},
+		{
+			name: "requiredWithDefault not given as a pointer should give an error",
+			arg:  requiredWithDefault{},
+			want: want{true, 1},
+		},
+		{
+			name: "invalidDefault should give an internal error",
+			arg:  invalidDefault{},
+			want: want{false, 1},
+		},
 	}
 
 	runValidateStructTests(tests, t)
+
+	t.Run("defaults should be assigned to the nil fields of a pointer", func(t *testing.T) {
+		d := defaults{}
+
+		res, ok := ValidateStruct(&d)
+		if !ok || len(res.Errors) != 0 {
+			t.Fatal(res.String())
+		}
+
+		if d.Int != 10 || d.Bool != true || d.String != "abc" {
+			t.Errorf("expected scalar defaults but got %#v", d)
+		}
+
+		if s, ok := d.Slice.([]string); !ok || s == nil || len(s) != 0 {
+			t.Errorf("expected an empty []string but got %#v", d.Slice)
+		}
+
+		if m, ok := d.Map.(map[string]any); !ok || m == nil || len(m) != 0 {
+			t.Errorf("expected an empty map[string]any but got %#v", d.Map)
+		}
+
+		if len(d.Tags) != 1 || d.Tags[0] != "a" {
+			t.Errorf("expected Tags to be [a] but got %#v", d.Tags)
+		}
+
+		if d.Meta == nil {
+			t.Error("expected Meta to be an empty map but got nil")
+		}
+
+		if d.Limit == nil || *d.Limit != 20 {
+			t.Errorf("expected Limit to point to 20 but got %v", d.Limit)
+		}
+
+		if len(d.Ints) != 2 || d.Ints[0] != 1 || d.Ints[1] != 2 {
+			t.Errorf("expected Ints to be [1 2] but got %#v", d.Ints)
+		}
+
+		if len(d.Counts) != 2 || d.Counts["a"] != 1 || d.Counts["b"] != 2 {
+			t.Errorf("expected Counts to be map[a:1 b:2] but got %#v", d.Counts)
+		}
+	})
+
+	t.Run("defaults should not be assigned when not given a pointer", func(t *testing.T) {
+		d := defaults{}
+
+		ValidateStruct(d)
+
+		if d.Int != nil || d.Tags != nil || d.Limit != nil {
+			t.Errorf("expected the fields to stay nil but got %#v", d)
+		}
+	})
+
+	t.Run("defaults should not replace the given values", func(t *testing.T) {
+		d := defaults{Int: 1, Tags: []string{}}
+
+		ValidateStruct(&d)
+
+		if d.Int != 1 || len(d.Tags) != 0 {
+			t.Errorf("expected the given values to stay but got %#v", d)
+		}
+	})
+}
+
+func TestCrossField(t *testing.T) {
+	type signUp struct {
+		Password any `vx:"name=password, type=string"`
+		Confirm  any `vx:"name=confirm, type=string, eqField=password"`
+		Username any `vx:"name=username, type=string, neField=password"`
+	}
+
+	type dateRange struct {
+		From any `vx:"name=from, type=string, lteField=to"`
+		To   any `vx:"name=to, type=string"`
+	}
+
+	type numberRange struct {
+		Min any `vx:"name=min, ltField=max"`
+		Max any `vx:"name=max, gteField=min"`
+	}
+
+	type filter struct {
+		Created dateRange
+		Limit   any `vx:"name=limit, gtField=Amount.min"`
+		Amount  numberRange
+	}
+
+	type unknownRef struct {
+		A any `vx:"eqField=b"`
+		B any `vx:"gtField=Nested.a"`
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "signUp with matching password should not give an error",
+			arg: signUp{
+				Password: "secret",
+				Confirm:  "secret",
+				Username: "vx",
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "signUp with mismatching password should give errors",
+			arg: signUp{
+				Password: "secret",
+				Confirm:  "secrets",
+				Username: "secret",
+			},
+			want: want{true, 2},
+		},
+		{
+			name: "signUp with nil values should not give an error",
+			arg:  signUp{},
+			want: want{true, 0},
+		},
+		{
+			name: "filter with valid ranges should not give an error",
+			arg: filter{
+				Created: dateRange{From: "2022-01-01", To: "2022-12-31"},
+				Limit:   float64(10),
+				Amount:  numberRange{Min: 1, Max: 2.5},
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "filter with equal bounds should only give an error for the strict rule",
+			arg: filter{
+				Created: dateRange{From: "2022-01-01", To: "2022-01-01"},
+				Amount:  numberRange{Min: 1, Max: float64(1)},
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "filter with inverted ranges should give errors",
+			arg: filter{
+				Created: dateRange{From: "2023-01-01", To: "2022-12-31"},
+				Limit:   1,
+				Amount:  numberRange{Min: 3, Max: 2},
+			},
+			want: want{true, 4},
+		},
+		{
+			name: "filter with values that can't be compared should give errors",
+			arg: filter{
+				Amount: numberRange{Min: "1", Max: 2},
+			},
+			want: want{true, 2},
+		},
+		{
+			name: "unknownRef should give internal errors",
+			arg:  unknownRef{},
+			want: want{false, 2},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+}
+
+func TestRequiredIf(t *testing.T) {
+	type assoc struct {
+		AssocID   any `vx:"name=assocId, requiredWithout=assocName"`
+		AssocName any `vx:"name=assocName"`
+	}
+
+	type filter struct {
+		Kind      any `vx:"name=kind, type=string"`
+		BonusType any `vx:"name=bonusType, requiredIf=kind bonus"`
+		Reason    any `vx:"name=reason, requiredUnless=kind bonus"`
+		Assoc     assoc
+		Note      any `vx:"name=note, requiredWith=Assoc.assocId Assoc.assocName"`
+	}
+
+	type inMap struct {
+		Filter map[string]any `vx:"name=filter"`
+		Limit  any            `vx:"name=limit, requiredIf=filter.kind bonus"`
+	}
+
+	type unknownRef struct {
+		A any `vx:"requiredIf=b bonus"`
+		B any `vx:"requiredWith=a c"`
+	}
+
+	type invalidArgs struct {
+		A any `vx:"requiredIf=b"`
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "filter for bonus with bonusType should not give an error",
+			arg: filter{
+				Kind:      "bonus",
+				BonusType: "referral",
+				Assoc:     assoc{AssocID: 1},
+				Note:      "note",
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "filter for bonus without bonusType should give an error",
+			arg: filter{
+				Kind:  "bonus",
+				Assoc: assoc{AssocName: "vx"},
+				Note:  "note",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "filter for assoc without reason and assoc should give errors",
+			arg: filter{
+				Kind: "assoc",
+			},
+			want: want{true, 2},
+		},
+		{
+			name: "filter with assoc but without note should give an error",
+			arg: filter{
+				Kind:   "assoc",
+				Reason: "because",
+				Assoc:  assoc{AssocID: 1},
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "inMap for bonus without limit should give an error",
+			arg: inMap{
+				Filter: map[string]any{"kind": "bonus"},
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "inMap for assoc without limit should not give an error",
+			arg: inMap{
+				Filter: map[string]any{"kind": "assoc"},
+			},
+			want: want{true, 0},
+		},
+		{
+			name: "inMap without a filter should not give an error",
+			arg:  inMap{},
+			want: want{true, 0},
+		},
+		{
+			name: "unknownRef should give internal errors",
+			arg:  unknownRef{},
+			want: want{false, 2},
+		},
+		{
+			name: "invalidArgs should give an internal error",
+			arg:  invalidArgs{},
+			want: want{false, 1},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+}
+
+func TestValidationGroups(t *testing.T) {
+	RegisterGroups("create", "update", "list")
+
+	type user struct {
+		_     struct{} `vx:"atLeastOne@list=name email"`
+		ID    any      `vx:"name=id, required@update"`
+		Name  any      `vx:"name=name, type=string, required@create|update, minLength=3"`
+		Email any      `vx:"name=email, type=string, required(allowZero)@create"`
+	}
+
+	type createOnly struct {
+		Password any `vx:"name=password, required@create"`
+	}
+
+	type unknownGroup struct {
+		_ struct{} `vx:"exclusive@lst=a b"`
+		A any      `vx:"name=a, required@creat"`
+		B any      `vx:"name=b"`
+	}
+
+	type emptyGroup struct {
+		A any `vx:"required@"`
+	}
+
+	type groupOnType struct {
+		A any `vx:"type@create=string"`
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "user without groups should only run the rules without a group",
+			arg: user{
+				Name: "ab",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "user for create without name and email should give errors",
+			arg:  user{},
+			opts: []Option{WithGroups("create")},
+			want: want{true, 2},
+		},
+		{
+			name: "user for update without id and name should give errors",
+			arg: user{
+				Email: "a@b.c",
+			},
+			opts: []Option{WithGroups("update")},
+			want: want{true, 2},
+		},
+		{
+			name: "user for list without name and email should give an error",
+			arg:  user{},
+			opts: []Option{WithGroups("list")},
+			want: want{true, 1},
+		},
+		{
+			name: "user for create and update should run the rules of both",
+			arg:  user{},
+			opts: []Option{WithGroups("create", "update")},
+			want: want{true, 3},
+		},
+		{
+			name: "user for an unknown group should give an internal error",
+			arg:  user{},
+			opts: []Option{WithGroups("creat")},
+			want: want{false, 1},
+		},
+		{
+			name: "unknownGroup should give internal errors",
+			arg:  unknownGroup{},
+			want: want{false, 2},
+		},
+		{
+			name: "createOnly for update should not give an error",
+			arg:  createOnly{},
+			opts: []Option{WithGroups("update")},
+			want: want{true, 0},
+		},
+		{
+			name: "createOnly for create should give an error",
+			arg:  createOnly{},
+			opts: []Option{WithGroups("create")},
+			want: want{true, 1},
+		},
+		{
+			name: "emptyGroup should give an internal error",
+			arg:  emptyGroup{},
+			want: want{false, 1},
+		},
+		{
+			name: "groupOnType should give an internal error",
+			arg:  groupOnType{},
+			want: want{false, 1},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+}
+
+func TestPartialValidation(t *testing.T) {
+	type period struct {
+		_    struct{} `vx:"allOrNone=from to"`
+		From any      `vx:"name=from, type=string, lteField=to"`
+		To   any      `vx:"name=to, type=string"`
+	}
+
+	type patch struct {
+		Name   any `vx:"name=name, type=string, required, minLength=3"`
+		Email  any `vx:"name=email, type=string, required"`
+		Period period
+	}
+
+	invalid := patch{Name: "vx", Period: period{From: "2023-01-01", To: "2022-01-01"}}
+	halfPeriod := patch{Period: period{From: "2023-01-01"}}
+
+	tests := []validateStructTest{
+		{
+			name: "all fields should give errors",
+			arg:  invalid,
+			want: want{true, 3},
+		},
+		{
+			name: "only name should give an error",
+			arg:  invalid,
+			opts: []Option{OnlyFields("name")},
+			want: want{true, 1},
+		},
+		{
+			name: "only the nested struct should run its cross field rule",
+			arg:  invalid,
+			opts: []Option{OnlyFields("Period")},
+			want: want{true, 1},
+		},
+		{
+			name: "only a part of the cross field rule should not run it",
+			arg:  invalid,
+			opts: []Option{OnlyFields("Period.from")},
+			want: want{true, 0},
+		},
+		{
+			name: "only a part of the field group should not run it",
+			arg:  halfPeriod,
+			opts: []Option{OnlyFields("Period.from")},
+			want: want{true, 0},
+		},
+		{
+			name: "the whole field group should run it",
+			arg:  halfPeriod,
+			opts: []Option{OnlyFields("Period")},
+			want: want{true, 1},
+		},
+		{
+			name: "except name and period should give an error",
+			arg:  invalid,
+			opts: []Option{ExceptFields("name", "Period")},
+			want: want{true, 1},
+		},
+		{
+			name: "unknown field should give an internal error",
+			arg:  invalid,
+			opts: []Option{OnlyFields("nmae")},
+			want: want{false, 1},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+
+	t.Run("ValidateFields and ValidateExcept should select the fields", func(t *testing.T) {
+		if res, _ := ValidateFields(invalid, "name"); len(res.Errors) != 1 {
+			t.Errorf("expected 1 error but got %d: %s", len(res.Errors), res.String())
+		}
+
+		if res, _ := ValidateExcept(invalid, "name"); len(res.Errors) != 2 {
+			t.Errorf("expected 2 errors but got %d: %s", len(res.Errors), res.String())
+		}
+	})
+}
+
+func TestErrorLimits(t *testing.T) {
+	type user struct {
+		Name  any `vx:"name=name, type=string, minLength=3, eqField=email"`
+		Email any `vx:"name=email, type=string, required"`
+		Age   any `vx:"name=age, type=int, required"`
+	}
+
+	invalid := user{Name: "ab", Email: "x", Age: "old"}
+
+	tests := []validateStructTest{
+		{
+			name: "user without limits should give all the errors",
+			arg:  invalid,
+			want: want{true, 3},
+		},
+		{
+			name: "user with fail fast should give an error",
+			arg:  invalid,
+			opts: []Option{FailFast()},
+			want: want{true, 1},
+		},
+		{
+			name: "user with max 2 errors should give 2 errors",
+			arg:  invalid,
+			opts: []Option{MaxErrors(2)},
+			want: want{true, 2},
+		},
+		{
+			name: "user with max 10 errors should give all the errors",
+			arg:  invalid,
+			opts: []Option{MaxErrors(10)},
+			want: want{true, 3},
+		},
+		{
+			name: "user with the first error per field should give an error for each field",
+			arg:  invalid,
+			opts: []Option{FirstErrorPerField()},
+			want: want{true, 2},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+
+	t.Run("result should be truncated only when the validation stopped", func(t *testing.T) {
+		if res, _ := ValidateStruct(invalid); res.Truncated {
+			t.Error("expected the result without limits not to be truncated")
+		}
+
+		if res, _ := ValidateStruct(invalid, MaxErrors(10)); res.Truncated {
+			t.Error("expected the result under the limit not to be truncated")
+		}
+
+		if res, _ := ValidateStruct(invalid, FailFast()); !res.Truncated {
+			t.Error("expected the result with fail fast to be truncated")
+		}
+
+		if res, _ := ValidateStruct(invalid, FirstErrorPerField()); !res.Truncated {
+			t.Error("expected the result with the first error per field to be truncated")
+		}
+
+		single := user{Name: "abc", Email: "abc"}
+
+		if res, _ := ValidateStruct(single, FailFast()); len(res.Errors) != 1 || res.Truncated {
+			t.Errorf("expected a single error with fail fast not to be truncated but got %v, %v", res.Errors, res.Truncated)
+		}
+
+		if res, _ := ValidateStruct(single, MaxErrors(1)); len(res.Errors) != 1 || res.Truncated {
+			t.Errorf("expected a single error with max 1 error not to be truncated but got %v, %v", res.Errors, res.Truncated)
+		}
+
+		if res, _ := ValidateStruct(single, FirstErrorPerField()); len(res.Errors) != 1 || res.Truncated {
+			t.Errorf("expected a single error with the first error per field not to be truncated but got %v, %v", res.Errors, res.Truncated)
+		}
+	})
+
+	t.Run("fail fast should give the first error in the order of the fields", func(t *testing.T) {
+		res, _ := ValidateStruct(user{Name: "abc", Email: "abc"}, FailFast())
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "age is required" {
+			t.Errorf("expected only the error for age but got %s", res.String())
+		}
+	})
+}
+
+func TestShortCircuit(t *testing.T) {
+	type wrongType struct {
+		A any `vx:"type=string, minLength=3"`
+	}
+
+	type wrongTypeRef struct {
+		A any `vx:"type=int"`
+		B any `vx:"type=int, gtField=A"`
+	}
+
+	type bail struct {
+		A any `vx:"type=string, bail, notBlank, minLength=3, eqField=B"`
+		B any `vx:"type=string"`
+	}
+
+	type noBail struct {
+		A any `vx:"type=string, notBlank, minLength=3, eqField=B"`
+		B any `vx:"type=string"`
+	}
+
+	type bailInGroup struct {
+		A any `vx:"bail@create"`
+	}
+
+	tests := []validateStructTest{
+		{
+			name: "wrongType with int value should only give the type error",
+			arg: wrongType{
+				A: 69,
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "wrongType with short string value should give an error",
+			arg: wrongType{
+				A: "ab",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "wrongTypeRef with string A should not run the cross field rule",
+			arg: wrongTypeRef{
+				A: "1",
+				B: 0,
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "bail with a blank value should only give the first error",
+			arg: bail{
+				A: " ",
+				B: "b",
+			},
+			want: want{true, 1},
+		},
+		{
+			name: "noBail with a blank value should give all the errors",
+			arg: noBail{
+				A: " ",
+				B: "b",
+			},
+			want: want{true, 3},
+		},
+		{
+			name: "bailInGroup should give an internal error",
+			arg:  bailInGroup{},
+			want: want{false, 1},
+		},
+	}
+
+	runValidateStructTests(tests, t)
+}
+
+func TestValidateValue(t *testing.T) {
+	RegisterGroups("create")
+
+	tests := []struct {
+		name string
+		arg  any
+		tag  string
+		opts []Option
+		want want
+	}{
+		{
+			name: "string with minLength should not give an error",
+			arg:  "abcdefgh",
+			tag:  "name=id, type=string, required, minLength=8",
+			want: want{true, 0},
+		},
+		{
+			name: "short string with minLength should give an error",
+			arg:  "abc",
+			tag:  "name=id, type=string, required, minLength=8",
+			want: want{true, 1},
+		},
+		{
+			name: "nil with required should give an error",
+			arg:  nil,
+			tag:  "required",
+			want: want{true, 1},
+		},
+		{
+			name: "int with type=string should give only the type error",
+			arg:  69,
+			tag:  "type=string, minLength=3",
+			want: want{true, 1},
+		},
+		{
+			name: "JSON number with type=int should not give an error",
+			arg:  float64(42),
+			tag:  "type=int",
+			want: want{true, 0},
+		},
+		{
+			name: "string with type=int and coercion should not give an error",
+			arg:  "42",
+			tag:  "type=int",
+			opts: []Option{WithCoercion()},
+			want: want{true, 0},
+		},
+		{
+			name: "nil with default should give an error, there is nothing to fill",
+			arg:  nil,
+			tag:  "type=int, default=20, required",
+			want: want{true, 1},
+		},
+		{
+			name: "rule in a selected group should give an error",
+			arg:  "",
+			tag:  "required@create",
+			opts: []Option{WithGroups("create")},
+			want: want{true, 1},
+		},
+		{
+			name: "cross field rule should give an internal error",
+			arg:  "abc",
+			tag:  "eqField=password",
+			want: want{false, 1},
+		},
+		{
+			name: "unknown type should give an internal error",
+			arg:  "abc",
+			tag:  "type=nope",
+			want: want{false, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, ok := ValidateValue(test.arg, test.tag, test.opts...)
+
+			if ok != test.want.ok {
+				t.Error(res.String())
+				t.Errorf("expected ok to be %v but got %v, check the errors above", test.want.ok, ok)
+			}
+
+			if len(res.Errors) != test.want.count {
+				t.Error(res.String())
+				t.Errorf("expected to get exactly %v number of validation errors but got %v, check the errors above.", test.want.count, len(res.Errors))
+			}
+		})
+	}
+
+	t.Run("errors should be named after name= in the tag", func(t *testing.T) {
+		res, _ := ValidateValue("", "name=id, required")
+
+		if len(res.Errors) != 1 || !strings.HasPrefix(res.Errors[0].Error(), "id ") {
+			t.Errorf("expected a single error about id but got %v", res.StringArray())
+		}
+	})
+}
+
+func TestVxResultJSON(t *testing.T) {
+	res := VxResult{
+		Errors: []error{
+			&FieldError{
+				Field:    "items[1].sku",
+				Path:     Path{}.Key("items").Index(1).Key("sku"),
+				Code:     "minLength",
+				Params:   map[string]any{"limit": 3},
+				Position: Position{Offset: 12, Line: 2, Column: 5},
+				Err:      errors.New("items[1].sku is too short"),
+			},
+			&FieldError{Field: "", Path: Path{}, Code: "exclusive", Err: errors.New("only one of assoc, bonus can be present")},
+			errors.New("selected an unknown field nope"),
+		},
+		Truncated: true,
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("VxResult should marshal to the documented schema", func(t *testing.T) {
+		expected := `{"errors":[` +
+			`{"field":"items[1].sku","path":["items",1,"sku"],"code":"minLength","message":"items[1].sku is too short","params":{"limit":3},"position":{"offset":12,"line":2,"column":5}},` +
+			`{"field":"","path":[],"code":"exclusive","message":"only one of assoc, bonus can be present"},` +
+			`{"message":"selected an unknown field nope"}` +
+			`],"truncated":true}`
+
+		if string(data) != expected {
+			t.Errorf("expected %s but got %s", expected, data)
+		}
+	})
+
+	t.Run("VxResult should unmarshal back to the same result", func(t *testing.T) {
+		var got VxResult
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if !got.Truncated || got.String() != res.String() {
+			t.Errorf("expected %v but got %v", res.StringArray(), got.StringArray())
+		}
+
+		var fieldErr *FieldError
+		if !errors.As(got.Errors[0], &fieldErr) || fieldErr.Path.JSONPointer() != "/items/1/sku" || fieldErr.Position.Line != 2 || fieldErr.Params["limit"] != float64(3) {
+			t.Errorf("expected the first error to come back as it was but got %+v", got.Errors[0])
+		}
+
+		if errors.As(got.Errors[2], &fieldErr) {
+			t.Errorf("expected the internal error to be a plain error but got %+v", got.Errors[2])
+		}
+	})
+
+	t.Run("result of a validation should survive the round trip", func(t *testing.T) {
+		res, _ := ValidateJSON(SchemaOf[docOrder](), []byte(`{"id": 1}`))
+
+		data, err := json.Marshal(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got VxResult
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.String() != res.String() || strings.Join(got.Paths(JSONPointer), " ") != strings.Join(res.Paths(JSONPointer), " ") {
+			t.Errorf("expected %v but got %v", res.StringArray(), got.StringArray())
+		}
+	})
+}
+
+func TestVxResultAccessors(t *testing.T) {
+	res, _ := ValidateJSON(SchemaOf[docOrder](), []byte(`{"id": 1, "email": " ", "address": {"city": "Pune"}, "items": [{"sku": "ab", "quantity": 1}, {"quantity": 2.5}]}`))
+
+	t.Run("HasErrors should tell if there are errors", func(t *testing.T) {
+		if !res.HasErrors() || (VxResult{}).HasErrors() {
+			t.Errorf("expected only the result with errors to have them")
+		}
+	})
+
+	t.Run("Err should be nil without errors", func(t *testing.T) {
+		if err := (VxResult{Errors: []error{}}).Err(); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+
+	t.Run("Err should work with errors.Is and errors.As", func(t *testing.T) {
+		err := res.Err()
+
+		if !errors.Is(err, ErrNotWholeNumber) {
+			t.Errorf("expected the error to be ErrNotWholeNumber, got %v", err)
+		}
+
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != "id" {
+			t.Errorf("expected the first FieldError to be about id but got %v", fieldErr)
+		}
+	})
+
+	t.Run("ByField should group the errors by their fields", func(t *testing.T) {
+		byField := res.ByField()
+
+		if len(byField["id"]) != 1 || len(byField["email"]) != 1 || len(byField["items[1].sku"]) != 1 || len(byField["items[1].quantity"]) != 1 || len(byField["items[0].sku"]) != 1 {
+			t.Errorf("expected an error for each field but got %v", byField)
+		}
+	})
+
+	t.Run("FilterCode should keep the errors with the codes", func(t *testing.T) {
+		if got := res.FilterCode("type"); len(got.Errors) != 2 {
+			t.Errorf("expected 2 type errors but got %v", got.StringArray())
+		}
+
+		if got := res.FilterCode("required", "notBlank"); len(got.Errors) != 2 {
+			t.Errorf("expected 2 required and notBlank errors but got %v", got.StringArray())
+		}
+	})
+
+	t.Run("FilterPath should keep the errors under the path", func(t *testing.T) {
+		if got := res.FilterPath(Path{}.Key("items")); len(got.Errors) != 3 {
+			t.Errorf("expected 3 errors under items but got %v", got.StringArray())
+		}
+
+		if got := res.FilterPath(Path{}.Key("items").Index(1)); len(got.Errors) != 2 {
+			t.Errorf("expected 2 errors under items[1] but got %v", got.StringArray())
+		}
+
+		if got := res.FilterPath(Path{}); len(got.Errors) != len(res.Errors) {
+			t.Errorf("expected all the errors under the root but got %v", got.StringArray())
+		}
+	})
+}
+
+func TestJSONTypeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		tag      string
+		expected string
+	}{
+		{"default", "a", "name=age, type=float64", "age should be of type number but got string"},
+		{"kind", true, "name=scores, type=map[string]float64", "scores should be of type object but got boolean"},
+		{"slice", []any{"a", 1.0}, "name=tags, type=[]string", "tags should be an array of elem of type string but got number"},
+		{"array", [2]int{1, 2}, "name=pair, type=[2]string", "pair should be an array of elem of type string but got integer"},
+		{"array length", []string{"a"}, "name=pair, type=[2]string", "pair should be of type array of string with 2 items but got array of string"},
+		{"map key", map[string]any{"a": 1.0}, "name=scores, type=map[int]float64", "scores should be an object with keys of type integer but got keys of type string"},
+		{"map elem", map[string]any{"a": "x"}, "name=scores, type=map[string]float64", "scores should be an object with values of type number but got values of type string"},
+		{"same JSON type", int8(3), "name=count, type=int", "count should be of type int but got int8"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, _ := ValidateValue(tc.value, tc.tag, WithJSONTypeNames())
+
+			if len(res.Errors) != 1 || res.Errors[0].Error() != tc.expected {
+				t.Errorf("expected %q but got %v", tc.expected, res.Errors)
+			}
+		})
+	}
+
+	t.Run("params should have the types named in the message", func(t *testing.T) {
+		params := []struct {
+			value    any
+			tag      string
+			opts     []Option
+			expected string
+			actual   string
+		}{
+			{"a", "name=age, type=float64", []Option{WithJSONTypeNames()}, "number", "string"},
+			{[]any{"a", 1.0}, "name=tags, type=[]string", nil, "string", "float64"},
+			{[]any{"a", 1.0}, "name=tags, type=[]string", []Option{WithJSONTypeNames()}, "string", "number"},
+			{map[string]any{"a": 1.0}, "name=scores, type=map[int]float64", nil, "int", "string"},
+			{map[string]any{"a": "x"}, "name=scores, type=map[string]float64", []Option{WithJSONTypeNames()}, "number", "string"},
+		}
+
+		for _, p := range params {
+			res, _ := ValidateValue(p.value, p.tag, p.opts...)
+
+			var fieldErr *FieldError
+			if len(res.Errors) != 1 || !errors.As(res.Errors[0], &fieldErr) {
+				t.Fatalf("%s: expected a field error but got %v", p.tag, res.Errors)
+			}
+
+			if fieldErr.Params["expected"] != p.expected || fieldErr.Params["actual"] != p.actual {
+				t.Errorf("%s: expected %s and %s but got %v", p.tag, p.expected, p.actual, fieldErr.Params)
+			}
+		}
+	})
+
+	t.Run("conversions should use the JSON type names", func(t *testing.T) {
+		conversions := []struct {
+			value    any
+			tag      string
+			opts     []Option
+			expected string
+		}{
+			{"abc", "type=int", []Option{WithCoercion()}, `value should be of type integer but got "abc"`},
+			{"yes!", "type=bool", []Option{WithCoercion()}, `value should be of type boolean but got "yes!"`},
+			{"1e20", "type=int", []Option{WithCoercion()}, "value is out of range for type integer, got 1e+20"},
+			{1e20, "type=int", nil, "value is out of range for type integer, got 1e+20"},
+			{json.Number("1e20"), "type=int", nil, "value is out of range for type integer, got 1e20"},
+		}
+
+		for _, c := range conversions {
+			res, _ := ValidateValue(c.value, c.tag, append(c.opts, WithJSONTypeNames())...)
+
+			if len(res.Errors) != 1 || res.Errors[0].Error() != c.expected {
+				t.Errorf("expected %q but got %v", c.expected, res.Errors)
+			}
+		}
+
+		res, _ := ValidateValue("abc", "type=int", WithCoercion())
+		if len(res.Errors) != 1 || res.Errors[0].Error() != `value should be of type int but got "abc"` {
+			t.Errorf("expected the Go type name without the option but got %v", res.Errors)
+		}
+	})
+
+	t.Run("Go type names should be the default", func(t *testing.T) {
+		res, _ := ValidateValue("a", "name=age, type=float64")
+
+		if len(res.Errors) != 1 || res.Errors[0].Error() != "age should be of type float64 but got string" {
+			t.Errorf("expected the Go type names but got %v", res.Errors)
+		}
+	})
 }
 
 // func TestValidateStruct(t *testing.T) {